@@ -1,16 +1,32 @@
 package main
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"mime/multipart"
+	"net/http"
+	"net/http/pprof"
 	"os"
 	"os/signal"
+	"strconv"
+	"strings"
 	"syscall"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 
-	"github.com/openBPL/internal/config"
-	"github.com/openBPL/pkg/core"
 	"github.com/spf13/cobra"
+	"openbpl/internal/config"
+	"openbpl/internal/database"
+	"openbpl/internal/database/migrations"
+	"openbpl/internal/logging"
+	"openbpl/internal/systemd"
+	"openbpl/pkg/core"
+	"openbpl/pkg/hub"
+	"openbpl/pkg/offline"
 )
 
 var (
@@ -19,7 +35,16 @@ var (
 	buildTime = "unknown"
 )
 
+// defaultOfflineQueuePath is where run/sync keep the local fallback queue
+// of events/detections that couldn't be saved while the storage backend
+// was unreachable.
+const defaultOfflineQueuePath = "openbpl-offline.db"
+
 func main() {
+	// Tee log.Printf output into an in-memory ring buffer so "openbpl
+	// diagnostics" can include the last N lines in its bundle.
+	logging.CaptureRecent(os.Stderr)
+
 	if err := rootCmd.Execute(); err != nil {
 		log.Fatal(err)
 	}
@@ -42,6 +67,15 @@ on detected threats according to your rules.`,
 	RunE: runMonitoring,
 }
 
+var syncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Drain the offline action queue against the live backend",
+	Long: `Replay events and detection results that were queued locally while
+the storage backend was unreachable, retrying with exponential backoff
+until the queue is empty.`,
+	RunE: runSync,
+}
+
 var configCmd = &cobra.Command{
 	Use:   "config",
 	Short: "Configuration management commands",
@@ -55,6 +89,64 @@ var configInitCmd = &cobra.Command{
 	RunE:  initConfig,
 }
 
+var migrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Manage the application database schema",
+	Long:  "Apply and inspect schema migrations against the Postgres database behind DATABASE_URL.",
+}
+
+var migrateUpCmd = &cobra.Command{
+	Use:   "up",
+	Short: "Apply every pending migration",
+	RunE:  runMigrateUp,
+}
+
+var migrateDownCmd = &cobra.Command{
+	Use:   "down [steps]",
+	Short: "Roll back the given number of migrations (default 1)",
+	Args:  cobra.MaximumNArgs(1),
+	RunE:  runMigrateDown,
+}
+
+var migrateStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the schema's current migration version",
+	RunE:  runMigrateStatus,
+}
+
+var hubCmd = &cobra.Command{
+	Use:   "hub",
+	Short: "Manage community-contributed detector configs",
+	Long: `Install, update, and pin detector configs (favicon reference sets,
+keyword lists, rules, brand profiles) distributed via the OpenBPL hub index.`,
+}
+
+var hubInstallCmd = &cobra.Command{
+	Use:   "install <name>[@version]",
+	Short: "Install a hub item, or its latest version if none is given",
+	Args:  cobra.ExactArgs(1),
+	RunE:  hubInstall,
+}
+
+var hubUpdateCmd = &cobra.Command{
+	Use:   "update",
+	Short: "Update every installed, unpinned hub item to its latest version",
+	RunE:  hubUpdate,
+}
+
+var hubListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List installed hub items",
+	RunE:  hubList,
+}
+
+var hubPinCmd = &cobra.Command{
+	Use:   "pin <name> <version>",
+	Short: "Pin an installed hub item to a specific version, exempting it from update",
+	Args:  cobra.ExactArgs(2),
+	RunE:  hubPin,
+}
+
 var versionCmd = &cobra.Command{
 	Use:   "version",
 	Short: "Show version information",
@@ -63,20 +155,35 @@ var versionCmd = &cobra.Command{
 	},
 }
 
+var diagnosticsCmd = &cobra.Command{
+	Use:   "diagnostics",
+	Short: "Generate a redacted support bundle for bug reports",
+	Long: `Build a zip archive containing resolved config (secrets masked), Go
+runtime stats, a goroutine dump, storage and source status, and this
+binary's version info. Written to stdout by default, or POSTed as
+multipart/form-data to --upload-url.`,
+	RunE: runDiagnostics,
+}
+
 func runMonitoring(cmd *cobra.Command, args []string) error {
 	configPath, _ := cmd.Flags().GetString("config")
 	dryRun, _ := cmd.Flags().GetBool("dry-run")
 	storageType, _ := cmd.Flags().GetString("storage")
 	duration, _ := cmd.Flags().GetDuration("duration")
+	debugAddr, _ := cmd.Flags().GetString("debug-addr")
+	offlineQueuePath, _ := cmd.Flags().GetString("offline-queue-path")
+	syncInterval, _ := cmd.Flags().GetDuration("sync-interval")
+	skipMigrate, _ := cmd.Flags().GetBool("skip-migrate")
 
 	log.Printf("🚀 Starting OpenBPL monitoring engine...")
 	log.Printf("📋 Config: %s", configPath)
 
 	// Load configuration
-	cfg, err := config.LoadFromFile(configPath)
+	configManager, err := config.NewManager(configPath)
 	if err != nil {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
+	cfg := configManager.Get()
 
 	// Override config with CLI flags
 	if dryRun {
@@ -91,21 +198,94 @@ func runMonitoring(cmd *cobra.Command, args []string) error {
 		log.Printf("🔍 Running in DRY-RUN mode (no enforcement actions will be taken)")
 	}
 
+	if cfg.Storage.Type == "postgres" && !skipMigrate {
+		if err := checkSchemaCurrent(cfg.Storage.DSN); err != nil {
+			return err
+		}
+	}
+
 	// Create and start the monitoring engine
 	engine, err := core.NewEngine(cfg)
 	if err != nil {
 		return fmt.Errorf("failed to create engine: %w", err)
 	}
+	defer engine.Close()
+
+	offlineQueue, err := openOfflineQueue(offlineQueuePath)
+	if err != nil {
+		return fmt.Errorf("failed to open offline queue: %w", err)
+	}
+	defer offlineQueue.Close()
+	engine.SetOfflineQueue(offlineQueue)
 
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
+	// Periodically replay events/detections that piled up in the offline
+	// queue while the storage backend was unreachable, without blocking on
+	// them the way the "sync" subcommand's backoff loop deliberately does.
+	go func() {
+		ticker := time.NewTicker(syncInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if offlineQueue.Len() == 0 {
+					continue
+				}
+				synced, err := engine.SyncOffline(offlineQueue.Len())
+				if err != nil {
+					log.Printf("⚠️ offline sync: replayed %d action(s), stopped at error: %v", synced, err)
+				} else if synced > 0 {
+					log.Printf("📤 offline sync: replayed %d action(s)", synced)
+				}
+			}
+		}
+	}()
+
+	// Profiling and /metrics live on their own listener rather than the
+	// main API server's, since they're meant for an operator reaching in
+	// from localhost/a trusted network, not something to expose alongside
+	// the public API.
+	var debugServer *http.Server
+	if debugAddr != "" {
+		debugServer = newDebugServer(debugAddr)
+		go func() {
+			log.Printf("🐞 Debug server (pprof + /metrics) starting on %s", debugAddr)
+			if err := debugServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				log.Printf("⚠️ debug server failed: %v", err)
+			}
+		}()
+		defer debugServer.Shutdown(context.Background())
+	}
+
+	// Log which live resources a reload would need to rebuild, so an
+	// operator tuning keywords/thresholds via the config file sees what
+	// actually took effect versus what still needs a restart today.
+	configManager.Subscribe(func(old, new *config.Config) {
+		if old.Monitoring.Sources.Certstream.URL != new.Monitoring.Sources.Certstream.URL ||
+			!equalStrings(old.Monitoring.Sources.Certstream.Keywords, new.Monitoring.Sources.Certstream.Keywords) {
+			log.Printf("♻️  certstream source config changed (url/keywords); restart to reconnect with the new settings")
+		}
+		if old.Enforcement.EmailAbuse.SMTP != new.Enforcement.EmailAbuse.SMTP {
+			log.Printf("♻️  SMTP config changed; restart to reconnect with the new settings")
+		}
+	})
+	if err := configManager.Watch(ctx, configPath); err != nil {
+		log.Printf("⚠️ config hot-reload disabled: %v", err)
+	}
+
 	// Handle shutdown gracefully
 	go func() {
 		quit := make(chan os.Signal, 1)
 		signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 		<-quit
 		log.Println("🛑 Shutdown signal received...")
+		if err := systemd.NotifyStopping(); err != nil {
+			log.Printf("⚠️ systemd stopping notification failed: %v", err)
+		}
 		cancel()
 	}()
 
@@ -117,11 +297,280 @@ func runMonitoring(cmd *cobra.Command, args []string) error {
 		log.Printf("⏰ Will run for %s", duration)
 	}
 
+	// Tell systemd (Type=notify units only; a no-op otherwise) that startup
+	// is complete, and start pinging its watchdog for as long as the
+	// engine's sources stay healthy.
+	if err := systemd.NotifyReady(); err != nil {
+		log.Printf("⚠️ systemd readiness notification failed: %v", err)
+	}
+	go func() {
+		if err := systemd.RunWatchdog(ctx, engine.HealthCheck); err != nil {
+			log.Printf("⚠️ systemd watchdog loop stopped: %v", err)
+		}
+	}()
+
 	// Start monitoring
 	log.Printf("🎯 Starting monitoring engine...")
 	return engine.Run(ctx)
 }
 
+// runSync drains the offline queue against the live storage backend,
+// retrying with exponential backoff (capped at syncMaxBackoff) whenever a
+// replay attempt fails, until the queue is empty.
+func runSync(cmd *cobra.Command, args []string) error {
+	configPath, _ := cmd.Flags().GetString("config")
+	offlineQueuePath, _ := cmd.Flags().GetString("offline-queue-path")
+
+	cfg, err := config.LoadFromFile(configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	engine, err := core.NewEngine(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create engine: %w", err)
+	}
+	defer engine.Close()
+
+	offlineQueue, err := openOfflineQueue(offlineQueuePath)
+	if err != nil {
+		return fmt.Errorf("failed to open offline queue: %w", err)
+	}
+	defer offlineQueue.Close()
+	engine.SetOfflineQueue(offlineQueue)
+
+	const syncMaxBackoff = 5 * time.Minute
+	backoff := time.Second
+
+	for {
+		depth := offlineQueue.Len()
+		if depth == 0 {
+			fmt.Println("✅ Offline queue drained")
+			return nil
+		}
+
+		synced, err := engine.SyncOffline(depth)
+		if err != nil {
+			log.Printf("⚠️ sync: replayed %d/%d action(s), retrying in %s: %v", synced, depth, backoff, err)
+			time.Sleep(backoff)
+			if backoff *= 2; backoff > syncMaxBackoff {
+				backoff = syncMaxBackoff
+			}
+			continue
+		}
+
+		fmt.Printf("📤 Synced %d action(s)\n", synced)
+		backoff = time.Second
+	}
+}
+
+// openOfflineQueue opens the local offline action queue at path, rotating
+// a corrupt file aside and starting fresh rather than failing outright -
+// bbolt has no repair tool, and a corrupt queue shouldn't block startup.
+func openOfflineQueue(path string) (*offline.Queue, error) {
+	queue, err := offline.Open(path)
+	if err == nil {
+		return queue, nil
+	}
+	if !errors.Is(err, offline.ErrCorruptQueue) {
+		return nil, err
+	}
+
+	rotated := fmt.Sprintf("%s.corrupt.%d", path, time.Now().UnixNano())
+	log.Printf("⚠️ offline queue %s is corrupt, rotating it to %s: %v", path, rotated, err)
+	if renameErr := os.Rename(path, rotated); renameErr != nil && !os.IsNotExist(renameErr) {
+		return nil, fmt.Errorf("failed to rotate corrupt offline queue: %w", renameErr)
+	}
+	return offline.Open(path)
+}
+
+// runDiagnostics builds a support bundle from a one-off engine constructed
+// from configPath, the same way runSync does, then either writes it to
+// --output (stdout if unset) or uploads it to --upload-url.
+func runDiagnostics(cmd *cobra.Command, args []string) error {
+	configPath, _ := cmd.Flags().GetString("config")
+	outputPath, _ := cmd.Flags().GetString("output")
+	uploadURL, _ := cmd.Flags().GetString("upload-url")
+
+	cfg, err := config.LoadFromFile(configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	engine, err := core.NewEngine(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create engine: %w", err)
+	}
+	defer engine.Close()
+
+	engine.SetBuildInfo(version, commit, buildTime)
+	bundle, err := engine.Diagnostics()
+	if err != nil {
+		return fmt.Errorf("failed to build diagnostics bundle: %w", err)
+	}
+
+	if uploadURL != "" {
+		return uploadDiagnostics(uploadURL, bundle)
+	}
+	if outputPath != "" {
+		if err := os.WriteFile(outputPath, bundle, 0o600); err != nil {
+			return fmt.Errorf("failed to write diagnostics bundle: %w", err)
+		}
+		fmt.Printf("📦 Diagnostics bundle written to %s\n", outputPath)
+		return nil
+	}
+
+	_, err = os.Stdout.Write(bundle)
+	return err
+}
+
+// uploadDiagnostics POSTs bundle to uploadURL as multipart/form-data, under
+// a "bundle" file field, matching the shape a typical support-ticket file
+// upload endpoint expects.
+func uploadDiagnostics(uploadURL string, bundle []byte) error {
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+
+	part, err := mw.CreateFormFile("bundle", "diagnostics.zip")
+	if err != nil {
+		return fmt.Errorf("failed to build upload request: %w", err)
+	}
+	if _, err := part.Write(bundle); err != nil {
+		return fmt.Errorf("failed to build upload request: %w", err)
+	}
+	if err := mw.Close(); err != nil {
+		return fmt.Errorf("failed to build upload request: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, uploadURL, &body)
+	if err != nil {
+		return fmt.Errorf("failed to build upload request: %w", err)
+	}
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to upload diagnostics bundle: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("upload failed: server returned %s", resp.Status)
+	}
+	fmt.Printf("📤 Diagnostics bundle uploaded to %s\n", uploadURL)
+	return nil
+}
+
+// checkSchemaCurrent refuses to let runMonitoring start against a Postgres
+// backend whose schema hasn't been migrated to the latest version, so a
+// missed "openbpl migrate up" fails fast instead of surfacing later as
+// confusing SQL errors. Pass --skip-migrate to bypass this.
+func checkSchemaCurrent(databaseURL string) error {
+	db, err := database.Connect(databaseURL)
+	if err != nil {
+		return fmt.Errorf("failed to connect to check schema version: %w", err)
+	}
+	defer db.Close()
+
+	current, dirty, err := db.MigrationVersion()
+	if err != nil {
+		return fmt.Errorf("failed to check schema version: %w", err)
+	}
+	if dirty {
+		return fmt.Errorf("database schema is dirty at version %d (a previous migration failed partway); run 'openbpl migrate up' or fix it manually", current)
+	}
+
+	latest, err := migrations.LatestVersion()
+	if err != nil {
+		return fmt.Errorf("failed to determine latest migration version: %w", err)
+	}
+	if current < latest {
+		return fmt.Errorf("database schema is behind (at version %d, need %d); run 'openbpl migrate up', or pass --skip-migrate to start anyway", current, latest)
+	}
+	return nil
+}
+
+// migrateConnect opens the database the migrate subcommands operate
+// against, using --database-url if set and falling back to $DATABASE_URL
+// otherwise, matching how cmd/server picks up its database connection.
+func migrateConnect(cmd *cobra.Command) (*database.DB, error) {
+	databaseURL, _ := cmd.Flags().GetString("database-url")
+	if databaseURL == "" {
+		databaseURL = os.Getenv("DATABASE_URL")
+	}
+	if databaseURL == "" {
+		return nil, fmt.Errorf("no database URL: pass --database-url or set DATABASE_URL")
+	}
+	return database.Connect(databaseURL)
+}
+
+func runMigrateUp(cmd *cobra.Command, args []string) error {
+	db, err := migrateConnect(cmd)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	if err := db.Migrate(context.Background()); err != nil {
+		return fmt.Errorf("migrate up failed: %w", err)
+	}
+
+	version, _, err := db.MigrationVersion()
+	if err != nil {
+		return fmt.Errorf("failed to read schema version after migrating: %w", err)
+	}
+	fmt.Printf("✅ Schema is up to date (version %d)\n", version)
+	return nil
+}
+
+func runMigrateDown(cmd *cobra.Command, args []string) error {
+	steps := 1
+	if len(args) == 1 {
+		parsed, err := strconv.Atoi(args[0])
+		if err != nil || parsed <= 0 {
+			return fmt.Errorf("invalid step count %q: must be a positive integer", args[0])
+		}
+		steps = parsed
+	}
+
+	db, err := migrateConnect(cmd)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	if err := db.MigrateDown(context.Background(), steps); err != nil {
+		return fmt.Errorf("migrate down failed: %w", err)
+	}
+
+	version, _, err := db.MigrationVersion()
+	if err != nil {
+		return fmt.Errorf("failed to read schema version after rolling back: %w", err)
+	}
+	fmt.Printf("✅ Rolled back %d migration(s); now at version %d\n", steps, version)
+	return nil
+}
+
+func runMigrateStatus(cmd *cobra.Command, args []string) error {
+	db, err := migrateConnect(cmd)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	version, dirty, err := db.MigrationVersion()
+	if err != nil {
+		return fmt.Errorf("failed to read schema version: %w", err)
+	}
+
+	if dirty {
+		fmt.Printf("⚠️  Schema is at version %d but DIRTY (a previous migration failed partway)\n", version)
+		return nil
+	}
+	fmt.Printf("📋 Schema is at version %d\n", version)
+	return nil
+}
+
 func initConfig(cmd *cobra.Command, args []string) error {
 	configPath := "openbpl.yaml"
 
@@ -136,14 +585,142 @@ func initConfig(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// newHubClient loads the configured hub cache/index (via the --config flag
+// shared by every hub subcommand) and returns a ready hub.Hub.
+func newHubClient(cmd *cobra.Command) (*hub.Hub, error) {
+	configPath, _ := cmd.Flags().GetString("config")
+
+	cfg, err := config.LoadFromFile(configPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	return hub.NewHub(cfg.Hub.CacheDir, cfg.Hub.IndexURL, cfg.Hub.PublicKey)
+}
+
+// newDebugServer builds the pprof/metrics server for --debug-addr:
+// net/http/pprof's handlers (registered on http.DefaultServeMux by its own
+// init, so they're pulled in here via a dedicated mux instead) plus
+// Prometheus's /metrics, kept off the main API listener entirely.
+func newDebugServer(addr string) *http.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	mux.Handle("/metrics", promhttp.Handler())
+
+	return &http.Server{Addr: addr, Handler: mux}
+}
+
+// equalStrings reports whether a and b contain the same elements in the
+// same order.
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// splitNameVersion parses "name@version" into its parts; version is empty
+// when the argument has no "@".
+func splitNameVersion(arg string) (name, version string) {
+	if idx := strings.LastIndex(arg, "@"); idx != -1 {
+		return arg[:idx], arg[idx+1:]
+	}
+	return arg, ""
+}
+
+func hubInstall(cmd *cobra.Command, args []string) error {
+	h, err := newHubClient(cmd)
+	if err != nil {
+		return err
+	}
+
+	name, version := splitNameVersion(args[0])
+	if err := h.Install(name, version); err != nil {
+		return err
+	}
+
+	fmt.Printf("✅ Installed %s\n", name)
+	return nil
+}
+
+func hubUpdate(cmd *cobra.Command, args []string) error {
+	h, err := newHubClient(cmd)
+	if err != nil {
+		return err
+	}
+
+	if err := h.Update(); err != nil {
+		return err
+	}
+
+	fmt.Println("✅ Hub items updated")
+	return nil
+}
+
+func hubList(cmd *cobra.Command, args []string) error {
+	h, err := newHubClient(cmd)
+	if err != nil {
+		return err
+	}
+
+	for _, item := range h.List() {
+		pinned := ""
+		if item.Pinned {
+			pinned = " (pinned)"
+		}
+		fmt.Printf("%s@%s [%s]%s\n", item.Name, item.Version, item.Kind, pinned)
+	}
+	return nil
+}
+
+func hubPin(cmd *cobra.Command, args []string) error {
+	h, err := newHubClient(cmd)
+	if err != nil {
+		return err
+	}
+
+	if err := h.Pin(args[0], args[1]); err != nil {
+		return err
+	}
+
+	fmt.Printf("📌 Pinned %s to %s\n", args[0], args[1])
+	return nil
+}
+
 func init() {
 	// Add flags to run command
 	runCmd.Flags().StringP("config", "c", "openbpl.yaml", "Configuration file path")
 	runCmd.Flags().BoolP("dry-run", "d", false, "Run in dry-run mode (no enforcement actions)")
-	runCmd.Flags().StringP("storage", "s", "", "Storage backend (memory, sqlite, postgres)")
+	runCmd.Flags().StringP("storage", "s", "", "Storage backend (memory, sqlite, postgres, embedded-postgres)")
 	runCmd.Flags().Duration("duration", 0, "Run for specific duration (0 = run forever)")
+	runCmd.Flags().String("debug-addr", "", "Address for a /debug/pprof and /metrics listener, e.g. \"localhost:6060\" (disabled if unset)")
+	runCmd.Flags().String("offline-queue-path", defaultOfflineQueuePath, "Path to the local offline action queue file")
+	runCmd.Flags().Duration("sync-interval", 30*time.Second, "How often the background goroutine drains the offline queue")
+	runCmd.Flags().Bool("skip-migrate", false, "Start even if the Postgres schema is behind the latest migration")
+
+	syncCmd.Flags().StringP("config", "c", "openbpl.yaml", "Configuration file path")
+	syncCmd.Flags().String("offline-queue-path", defaultOfflineQueuePath, "Path to the local offline action queue file")
+
+	migrateCmd.PersistentFlags().String("database-url", "", "Postgres connection string (defaults to $DATABASE_URL)")
+	migrateCmd.AddCommand(migrateUpCmd, migrateDownCmd, migrateStatusCmd)
+
+	hubCmd.PersistentFlags().StringP("config", "c", "openbpl.yaml", "Configuration file path")
+	hubCmd.AddCommand(hubInstallCmd, hubUpdateCmd, hubListCmd, hubPinCmd)
+
+	diagnosticsCmd.Flags().StringP("config", "c", "openbpl.yaml", "Configuration file path")
+	diagnosticsCmd.Flags().String("output", "", "Write the bundle to this file instead of stdout")
+	diagnosticsCmd.Flags().String("upload-url", "", "POST the bundle here as multipart/form-data instead of writing it out")
 
 	// Build command tree
 	configCmd.AddCommand(configInitCmd)
-	rootCmd.AddCommand(runCmd, configCmd, versionCmd)
+	rootCmd.AddCommand(runCmd, syncCmd, configCmd, migrateCmd, hubCmd, diagnosticsCmd, versionCmd)
 }
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"database/sql"
 	"log"
 	"net/http"
 	"os"
@@ -9,9 +10,19 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"golang.org/x/crypto/acme/autocert"
+
 	"openbpl/internal/config"
+	"openbpl/internal/database"
+	"openbpl/internal/diagnostics"
 	"openbpl/internal/handlers"
+	"openbpl/internal/handlers/decisions"
+	"openbpl/internal/logging"
 	"openbpl/internal/middleware"
+	"openbpl/pkg/core/scenarios"
+	"openbpl/pkg/hub"
+	"openbpl/pkg/models"
 )
 
 func main() {
@@ -23,27 +34,146 @@ func main() {
 
 	mux := http.NewServeMux()
 
-	setupRoutes(mux)
+	scenariosDir := os.Getenv("SCENARIOS_DIR")
+	if scenariosDir == "" {
+		scenariosDir = "scenarios"
+	}
+	scenarioEngine, err := scenarios.NewEngine(scenariosDir)
+	if err != nil {
+		log.Fatalf("❌ Failed to load scenarios from %s: %v", scenariosDir, err)
+	}
+
+	decisionStore := decisions.NewStore()
+	decisionsCtx, stopDecisions := context.WithCancel(context.Background())
+	defer stopDecisions()
+	go decisionStore.Run(decisionsCtx, 30*time.Second)
+
+	machineRegistry := decisions.NewMachineRegistry()
+	decisionTTL := 1 * time.Hour
+	if ttl := os.Getenv("DECISION_TTL"); ttl != "" {
+		if parsed, err := time.ParseDuration(ttl); err == nil {
+			decisionTTL = parsed
+		}
+	}
+
+	hubCacheDir := os.Getenv("HUB_CACHE_DIR")
+	if hubCacheDir == "" {
+		hubCacheDir = "hub-cache"
+	}
+	hubClient, err := hub.NewHub(hubCacheDir, os.Getenv("HUB_INDEX_URL"), os.Getenv("HUB_PUBLIC_KEY"))
+	if err != nil {
+		log.Fatalf("❌ Failed to initialize hub: %v", err)
+	}
+
+	// The users/threats endpoints are only registered when a database is
+	// configured; DatabaseHandlers otherwise has nothing to query.
+	var databaseHandlers *handlers.DatabaseHandlers
+	var db *database.DB
+	// auditSink defaults to an in-memory trail; once a database is
+	// configured below, it's upgraded to one backed by the audit_log
+	// table so the trail survives a restart.
+	var auditSink middleware.AuditSink = middleware.NewMemoryAuditSink()
+	if databaseURL := os.Getenv("DATABASE_URL"); databaseURL != "" {
+		db, err = database.Connect(databaseURL)
+		if err != nil {
+			log.Fatalf("❌ Failed to connect to database: %v", err)
+		}
+		defer db.Close()
+		middleware.RegisterDBStats(nil, db.DB)
+		auditSink = middleware.NewDBAuditSink(db.DB)
+
+		databaseHandlers = handlers.NewDatabaseHandlers(
+			models.NewUserRepository(db.DB),
+			models.NewThreatRepository(db.DB),
+		)
+	}
+
+	diagnosticsHandlers := handlers.NewDiagnosticsHandlers(&serverDiagnostics{db: db})
+
+	setupRoutes(mux, cfg, scenarioEngine, decisionStore, machineRegistry, decisionTTL, hubClient, databaseHandlers, diagnosticsHandlers, auditSink)
+
+	appLogger := logging.New(cfg.Logging.Level, cfg.Logging.Format)
+
+	// AccessLog replaces Logger rather than stacking with it, so a request
+	// doesn't get logged twice.
+	requestLogger := middleware.Logger(appLogger)
+	if cfg.AccessLog.Enabled {
+		requestLogger = middleware.AccessLog(cfg.AccessLog)
+	}
 
 	server := &http.Server{
-		Addr:         cfg.Port,
-		Handler:      middleware.Chain(mux, middleware.Logger, middleware.CORS, middleware.Recovery),
-		ReadTimeout:  15 * time.Second,
-		WriteTimeout: 15 * time.Second,
-		IdleTimeout:  60 * time.Second,
-	}
-
-	go func() {
-		log.Printf("🚀 Server starting on %s", cfg.Port)
-		log.Printf("📖 Available endpoints:")
-		log.Printf("   GET  %s/health - Health check", cfg.Port)
-		log.Printf("   GET  %s/api/v1/status - Status info", cfg.Port)
-		log.Printf("   GET  %s/ - Home page", cfg.Port)
-
-		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			log.Fatal("❌ Server failed to start:", err)
+		Addr:         cfg.API.BindAddr,
+		Handler:      middleware.Chain(mux, middleware.ProxyHeaders(cfg.Security.ProxyHeaders), middleware.RequestID, requestLogger, middleware.Metrics, middleware.CORS(cfg.API.CORS), middleware.Recovery),
+		ReadTimeout:  cfg.API.ReadTimeout,
+		WriteTimeout: cfg.API.WriteTimeout,
+		IdleTimeout:  cfg.API.IdleTimeout,
+	}
+
+	// acmeChallengeServer serves Let's Encrypt HTTP-01 challenges on :80
+	// when autocert is enabled; nil otherwise, since a static cert/key pair
+	// or plain HTTP need no challenge listener.
+	var acmeChallengeServer *http.Server
+	switch {
+	case cfg.API.TLS.Autocert.Enabled:
+		manager := &autocert.Manager{
+			Prompt:     autocert.AcceptTOS,
+			HostPolicy: autocert.HostWhitelist(cfg.API.TLS.Autocert.Domains...),
+			Cache:      autocert.DirCache(cfg.API.TLS.Autocert.CacheDir),
+			Email:      cfg.API.TLS.Autocert.Email,
 		}
-	}()
+		server.TLSConfig = manager.TLSConfig()
+		acmeChallengeServer = &http.Server{Addr: ":80", Handler: manager.HTTPHandler(nil)}
+
+		go func() {
+			log.Printf("🔐 ACME HTTP-01 challenge server starting on :80")
+			if err := acmeChallengeServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				log.Fatal("❌ ACME challenge server failed to start:", err)
+			}
+		}()
+
+		go func() {
+			log.Printf("🚀 Server starting on %s (autocert TLS for %v)", cfg.API.BindAddr, cfg.API.TLS.Autocert.Domains)
+			if err := server.ListenAndServeTLS("", ""); err != nil && err != http.ErrServerClosed {
+				log.Fatal("❌ Server failed to start:", err)
+			}
+		}()
+	case cfg.API.TLS.CertFile != "" && cfg.API.TLS.KeyFile != "":
+		go func() {
+			log.Printf("🚀 Server starting on %s (TLS)", cfg.API.BindAddr)
+			if err := server.ListenAndServeTLS(cfg.API.TLS.CertFile, cfg.API.TLS.KeyFile); err != nil && err != http.ErrServerClosed {
+				log.Fatal("❌ Server failed to start:", err)
+			}
+		}()
+	default:
+		go func() {
+			log.Printf("🚀 Server starting on %s", cfg.API.BindAddr)
+			log.Printf("📖 Available endpoints:")
+			log.Printf("   GET  %s/health - Health check", cfg.API.BindAddr)
+			log.Printf("   GET  %s/api/v1/status - Status info", cfg.API.BindAddr)
+			log.Printf("   GET  %s/ - Home page", cfg.API.BindAddr)
+
+			if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				log.Fatal("❌ Server failed to start:", err)
+			}
+		}()
+	}
+
+	// When bind_addr is set, /metrics is kept off the main mux entirely and
+	// served on its own listener instead, so it can sit behind a
+	// firewalled/internal-only port rather than the public API port.
+	var metricsServer *http.Server
+	if cfg.Metrics.Enabled && cfg.Metrics.BindAddr != "" {
+		metricsMux := http.NewServeMux()
+		metricsMux.Handle("GET "+cfg.Metrics.Path, promhttp.Handler())
+		metricsServer = &http.Server{Addr: cfg.Metrics.BindAddr, Handler: metricsMux}
+
+		go func() {
+			log.Printf("📈 Metrics server starting on %s%s", cfg.Metrics.BindAddr, cfg.Metrics.Path)
+			if err := metricsServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				log.Fatal("❌ Metrics server failed to start:", err)
+			}
+		}()
+	}
 
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
@@ -57,15 +187,66 @@ func main() {
 	if err := server.Shutdown(ctx); err != nil {
 		log.Fatal("❌ Server forced to shutdown:", err)
 	}
+	if metricsServer != nil {
+		if err := metricsServer.Shutdown(ctx); err != nil {
+			log.Fatal("❌ Metrics server forced to shutdown:", err)
+		}
+	}
+	if acmeChallengeServer != nil {
+		if err := acmeChallengeServer.Shutdown(ctx); err != nil {
+			log.Fatal("❌ ACME challenge server forced to shutdown:", err)
+		}
+	}
 
 	log.Println("✅ Server stopped gracefully")
 }
 
-func setupRoutes(mux *http.ServeMux) {
+func setupRoutes(mux *http.ServeMux, cfg *config.Config, scenarioEngine *scenarios.Engine, decisionStore *decisions.Store, machineRegistry *decisions.MachineRegistry, decisionTTL time.Duration, hubClient *hub.Hub, databaseHandlers *handlers.DatabaseHandlers, diagnosticsHandlers *handlers.DiagnosticsHandlers, auditSink middleware.AuditSink) {
 	mux.HandleFunc("GET /health", handlers.Health)
 
 	mux.HandleFunc("GET /api/v1/status", handlers.Status)
 
+	if cfg.Metrics.Enabled && cfg.Metrics.BindAddr == "" {
+		mux.Handle("GET "+cfg.Metrics.Path, promhttp.Handler())
+	}
+
+	scenarioHandlers := handlers.NewScenarioHandlers(scenarioEngine)
+	mux.HandleFunc("POST /api/v1/scenarios/reload", scenarioHandlers.Reload)
+
+	// Central auth/rate-limit/audit stack for every admin- and agent-facing
+	// route below: Auth authenticates by bearer API key (or mTLS), RateLimit
+	// caps requests per client, and Audit records every mutating call.
+	tokenStore := middleware.StaticTokenStore{"admin": cfg.JWTSecret}
+	auth := middleware.Auth(tokenStore)
+	rateLimit := middleware.RateLimit(10, 20, nil)
+	audit := middleware.Audit(auditSink)
+	protect := func(h http.HandlerFunc) http.Handler {
+		return middleware.Chain(h, rateLimit, auth, audit)
+	}
+
+	decisionHandlers := decisions.NewHandlers(decisionStore, machineRegistry, decisionTTL)
+	machineAuth := machineRegistry.RequireMachineAuth
+	mux.Handle("GET /api/v1/decisions", protect(decisionHandlers.List))
+	mux.Handle("GET /api/v1/decisions/stream", protect(decisionHandlers.Stream))
+	// Pushed decisions are authenticated per-machine rather than against the
+	// shared admin token, so they go through machineAuth instead of auth.
+	mux.Handle("POST /api/v1/decisions", middleware.Chain(http.HandlerFunc(decisionHandlers.Create), rateLimit, machineAuth, audit))
+	mux.Handle("POST /api/v1/machines/register", protect(decisionHandlers.Register))
+	mux.HandleFunc("GET /api/v1/decisions/health", decisionHandlers.Health)
+
+	hubHandlers := handlers.NewHubHandlers(hubClient)
+	mux.HandleFunc("GET /api/v1/hub/items", hubHandlers.Items)
+	mux.Handle("POST /api/v1/hub/install", protect(hubHandlers.Install))
+
+	if databaseHandlers != nil {
+		mux.Handle("GET /api/v1/users", protect(databaseHandlers.ListUsers))
+		mux.Handle("GET /api/v1/users/{id}", protect(databaseHandlers.GetUser))
+		mux.Handle("GET /api/v1/threats", protect(databaseHandlers.ListThreats))
+		mux.Handle("GET /api/v1/threats/{id}", protect(databaseHandlers.GetThreat))
+	}
+
+	mux.Handle("GET /api/v1/diagnostics", protect(diagnosticsHandlers.Get))
+
 	staticHandler := http.StripPrefix("/static/", http.FileServer(http.Dir("./static")))
 	mux.HandleFunc("GET /static/{file...}", func(w http.ResponseWriter, r *http.Request) {
 		staticHandler.ServeHTTP(w, r)
@@ -77,3 +258,26 @@ func setupRoutes(mux *http.ServeMux) {
 	mux.HandleFunc("GET /", handlers.Home)
 	mux.HandleFunc("/{path...}", handlers.NotFound)
 }
+
+// serverDiagnostics implements handlers.Diagnostician for this binary. It
+// has no pkg/core.Engine to delegate to - unlike the CLI, this server
+// never runs the monitoring pipeline - so it reports on what it actually
+// holds: an optional database connection and its own runtime stats.
+type serverDiagnostics struct {
+	db *database.DB
+}
+
+func (s *serverDiagnostics) Diagnostics() ([]byte, error) {
+	var sqlDB *sql.DB
+	status := map[string]string{"database": "not configured"}
+	if s.db != nil {
+		sqlDB = s.db.DB
+		status["database"] = "configured"
+	}
+
+	return diagnostics.Bundle(diagnostics.Info{
+		Component:       "openbpl-server",
+		DB:              sqlDB,
+		ComponentStatus: status,
+	})
+}
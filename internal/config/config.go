@@ -3,20 +3,181 @@ package config
 
 import (
 	"fmt"
+	"net"
 	"os"
+	"strings"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
 
 // Config represents the main configuration structure
 type Config struct {
+	Engine      EngineConfig      `yaml:"engine"`
 	Monitoring  MonitoringConfig  `yaml:"monitoring"`
 	Enrichment  EnrichmentConfig  `yaml:"enrichment"`
 	Rules       RulesConfig       `yaml:"rules"`
+	Scenarios   ScenariosConfig   `yaml:"scenarios"`
+	Hub         HubConfig         `yaml:"hub"`
+	Decisions   DecisionsConfig   `yaml:"decisions"`
 	Enforcement EnforcementConfig `yaml:"enforcement"`
 	Storage     StorageConfig     `yaml:"storage"`
 	Logging     LoggingConfig     `yaml:"logging"`
+	API         APIConfig         `yaml:"api"`
+	Metrics     MetricsConfig     `yaml:"metrics"`
+	AccessLog   AccessLogConfig   `yaml:"access_log"`
+	Security    SecurityConfig    `yaml:"security"`
 	DryRun      bool              `yaml:"dry_run"`
+
+	// Environment ("development" or "production") backs IsDevelopment and
+	// IsProduction; the logger format comes from Logging.Format instead. It
+	// is also settable via YAML so LoadFromFile-based entrypoints (e.g.
+	// cmd/cli) get the same behavior as the env-driven Load() below.
+	Environment string `yaml:"environment"`
+
+	// The fields below are environment-driven rather than YAML-driven; they
+	// back the HTTP server entrypoint (cmd/server) via Load() instead of
+	// LoadFromFile().
+	Port      string        `yaml:"-"`
+	JWTSecret string        `yaml:"-"`
+	JWTExpiry time.Duration `yaml:"-"`
+}
+
+// Load builds configuration from environment variables for the HTTP server
+// entrypoint. Unlike LoadFromFile, there is no YAML file involved - most
+// values have an env var override, and every value has a
+// development-friendly default.
+func Load() *Config {
+	cfg := &Config{
+		Port:        getEnv("PORT", ":8080"),
+		Environment: getEnv("ENVIRONMENT", "development"),
+		JWTSecret:   getEnv("JWT_SECRET", "dev-secret-key"),
+		JWTExpiry:   parseDuration(getEnv("JWT_EXPIRY", "15m")),
+		API: APIConfig{
+			BindAddr:     getEnv("API_BIND_ADDR", getEnv("PORT", ":8080")),
+			ReadTimeout:  parseDuration(getEnv("API_READ_TIMEOUT", "15s")),
+			WriteTimeout: parseDuration(getEnv("API_WRITE_TIMEOUT", "15s")),
+			IdleTimeout:  parseDuration(getEnv("API_IDLE_TIMEOUT", "60s")),
+			TLS: TLSConfig{
+				CertFile: getEnv("TLS_CERT_FILE", ""),
+				KeyFile:  getEnv("TLS_KEY_FILE", ""),
+				Autocert: AutocertConfig{
+					Enabled:  getEnv("AUTOCERT_ENABLED", "false") == "true",
+					Domains:  splitNonEmpty(getEnv("AUTOCERT_DOMAINS", "")),
+					CacheDir: getEnv("AUTOCERT_CACHE_DIR", "autocert-cache"),
+					Email:    getEnv("AUTOCERT_EMAIL", ""),
+				},
+			},
+			CORS: CORSConfig{
+				AllowedOrigins: strings.Split(getEnv("CORS_ALLOWED_ORIGINS", "*"), ","),
+				AllowedMethods: []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
+				AllowedHeaders: []string{"Content-Type", "Authorization"},
+				MaxAge:         "10m",
+			},
+		},
+		Metrics: MetricsConfig{
+			Enabled:  getEnv("METRICS_ENABLED", "true") == "true",
+			Path:     getEnv("METRICS_PATH", "/metrics"),
+			BindAddr: getEnv("METRICS_BIND_ADDR", ""),
+		},
+		AccessLog: AccessLogConfig{
+			Enabled:    getEnv("ACCESS_LOG_ENABLED", "false") == "true",
+			Format:     getEnv("ACCESS_LOG_FORMAT", "combined"),
+			Output:     getEnv("ACCESS_LOG_OUTPUT", "stdout"),
+			Path:       getEnv("ACCESS_LOG_PATH", ""),
+			MaxSizeMB:  100,
+			MaxAgeDays: 28,
+			MaxBackups: 5,
+		},
+		Security: SecurityConfig{
+			ProxyHeaders: ProxyHeadersConfig{
+				Enabled:      getEnv("PROXY_HEADERS_ENABLED", "false") == "true",
+				TrustedCIDRs: splitNonEmptyOr(getEnv("PROXY_HEADERS_TRUSTED_CIDRS", ""), defaultTrustedCIDRs),
+			},
+		},
+	}
+
+	if cfg.IsProduction() && cfg.JWTSecret == "dev-secret-key" {
+		panic("refusing to start in production with the default JWT secret; set JWT_SECRET")
+	}
+
+	return cfg
+}
+
+// IsDevelopment reports whether the server is running in development mode.
+func (c *Config) IsDevelopment() bool {
+	return c.Environment == "development"
+}
+
+// IsProduction reports whether the server is running in production mode.
+func (c *Config) IsProduction() bool {
+	return c.Environment == "production"
+}
+
+// parseDuration parses s as a time.Duration, falling back to 15 minutes for
+// an empty or invalid value.
+func parseDuration(s string) time.Duration {
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 15 * time.Minute
+	}
+	return d
+}
+
+// getEnv returns the environment variable named key, or fallback if unset.
+func getEnv(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
+
+// splitNonEmpty splits raw on commas, returning nil for an empty string
+// rather than strings.Split's []string{""}.
+func splitNonEmpty(raw string) []string {
+	if raw == "" {
+		return nil
+	}
+	return strings.Split(raw, ",")
+}
+
+// splitNonEmptyOr is splitNonEmpty, falling back to fallback instead of nil
+// for an empty string.
+func splitNonEmptyOr(raw string, fallback []string) []string {
+	if raw == "" {
+		return fallback
+	}
+	return strings.Split(raw, ",")
+}
+
+// ScenariosConfig controls the YAML-driven scenario engine (see
+// pkg/core/scenarios), which replaces hardcoded keyword checks with
+// declarative rule files loaded from Dir.
+type ScenariosConfig struct {
+	Enabled bool   `yaml:"enabled"`
+	Dir     string `yaml:"dir"`
+}
+
+// EngineConfig controls the worker pool that processes events and
+// detections (see pkg/core/workers).
+type EngineConfig struct {
+	// Workers is the number of worker pool goroutines. Defaults to 4.
+	Workers int `yaml:"workers"`
+}
+
+// HubConfig controls the community "Hub" of installable detector configs
+// (see pkg/hub): favicon reference sets, keyword lists, YARA-style rules,
+// and brand profiles distributed via a Git-backed index.
+type HubConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// CacheDir is where installed items are cached on disk.
+	CacheDir string `yaml:"cache_dir"`
+	// IndexURL is the hub-index.yaml URL items are installed/updated from.
+	IndexURL string `yaml:"index_url"`
+	// PublicKey is the hub maintainer's hex-encoded ed25519 public key,
+	// required to verify hub-index.yaml's detached signature
+	// (IndexURL + ".sig") before any item in it is trusted.
+	PublicKey string `yaml:"public_key"`
 }
 
 type MonitoringConfig struct {
@@ -25,6 +186,10 @@ type MonitoringConfig struct {
 
 type SourcesConfig struct {
 	Certstream CertstreamConfig `yaml:"certstream"`
+	// Additional holds declaratively-configured sources (dnstwist, http_feed,
+	// etc.) looked up by Type in the pkg/core source registry, instead of
+	// getting a dedicated struct like Certstream above.
+	Additional []SourceConfig `yaml:"additional"`
 }
 
 type CertstreamConfig struct {
@@ -33,6 +198,14 @@ type CertstreamConfig struct {
 	Keywords []string `yaml:"keywords"`
 }
 
+// SourceConfig declares a source by registry Type name plus a free-form
+// Params map, for sources that don't need a first-class config struct.
+type SourceConfig struct {
+	Type    string                 `yaml:"type"`
+	Enabled bool                   `yaml:"enabled"`
+	Params  map[string]interface{} `yaml:"params"`
+}
+
 type EnrichmentConfig struct {
 	HTMLContent HTMLContentConfig `yaml:"html_content"`
 	Favicon     FaviconConfig     `yaml:"favicon"`
@@ -60,8 +233,26 @@ type FaviconSimilarityConfig struct {
 }
 
 type EnforcementConfig struct {
-	EmailAbuse EmailAbuseConfig `yaml:"email_abuse"`
-	Logger     LoggerConfig     `yaml:"logger"`
+	EmailAbuse  EmailAbuseConfig  `yaml:"email_abuse"`
+	Logger      LoggerConfig      `yaml:"logger"`
+	LAPIPublish LAPIPublishConfig `yaml:"lapi_publish"`
+}
+
+// LAPIPublishConfig controls the LAPIPublisher enforcer, which POSTs
+// confirmed threats to a peer OpenBPL instance's LAPI (see pkg/core's
+// lapi.go) instead of - or alongside - acting on them locally. This is the
+// agent half of the chunk0-4 LAPI split; the other half is the "lapi"
+// Source type registered for pulling decisions back down.
+type LAPIPublishConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// URL is the peer LAPI's base URL, e.g. "https://lapi.example.com".
+	URL string `yaml:"url"`
+	// APIKey is this machine's key, issued by the peer's
+	// POST /api/v1/machines/register.
+	APIKey string `yaml:"api_key"`
+	// TTL is how long a published decision lives before the peer expires
+	// it, e.g. "1h". Defaults to 1 hour.
+	TTL string `yaml:"ttl"`
 }
 
 type EmailAbuseConfig struct {
@@ -81,8 +272,19 @@ type LoggerConfig struct {
 	Enabled bool `yaml:"enabled"`
 }
 
+// DecisionsConfig controls whether high-severity detections are published
+// as LAPI-style decisions for bouncers to subscribe to (see
+// internal/handlers/decisions), and how long each decision lives.
+type DecisionsConfig struct {
+	Enabled bool   `yaml:"enabled"`
+	TTL     string `yaml:"ttl"` // e.g. "1h"; an invalid value falls back to 1 hour
+}
+
 type StorageConfig struct {
 	Type string `yaml:"type"` // memory, sqlite, postgres
+	// DSN is the sqlite file path (e.g. "openbpl.db") or the postgres
+	// connection URL, depending on Type. Unused for "memory".
+	DSN string `yaml:"dsn"`
 }
 
 type LoggingConfig struct {
@@ -90,6 +292,110 @@ type LoggingConfig struct {
 	Format string `yaml:"format"` // text, json
 }
 
+// AccessLogConfig controls middleware.AccessLog, the per-request HTTP log
+// (distinct from the application-level Logging above): NCSA/Apache Combined
+// Log Format lines, Traefik-style text, or JSON, written to stdout/stderr or
+// a rotating file.
+type AccessLogConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// Format is "text", "combined" (NCSA/Apache Combined Log Format), or
+	// "json".
+	Format string `yaml:"format"`
+	// Output is "stdout", "stderr", or "file". Defaults to "stdout".
+	Output string `yaml:"output"`
+	// Path is the log file path; only used when Output is "file".
+	Path string `yaml:"path"`
+	// MaxSizeMB is the file size, in megabytes, a log file is rotated at.
+	MaxSizeMB int `yaml:"max_size_mb"`
+	// MaxAgeDays is how long to retain old, rotated log files.
+	MaxAgeDays int `yaml:"max_age_days"`
+	// MaxBackups is how many rotated log files to retain.
+	MaxBackups int `yaml:"max_backups"`
+}
+
+// MetricsConfig controls the Prometheus /metrics endpoint (see
+// internal/middleware's Metrics middleware and HTTPMetrics).
+type MetricsConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// Path is the route /metrics is served on, e.g. "/metrics".
+	Path string `yaml:"path"`
+	// BindAddr, if set, serves /metrics on its own listener (e.g.
+	// ":9090") instead of the main server's mux, so it can be kept off a
+	// publicly reachable port.
+	BindAddr string `yaml:"bind_addr"`
+}
+
+// SecurityConfig groups policy around trusting the network OpenBPL runs on.
+type SecurityConfig struct {
+	ProxyHeaders ProxyHeadersConfig `yaml:"proxy_headers"`
+}
+
+// ProxyHeadersConfig controls middleware.ProxyHeaders, which recovers the
+// true client IP/scheme/host when OpenBPL runs behind a load balancer or
+// reverse proxy. TrustedCIDRs defaults to loopback + the RFC 1918 private
+// ranges, since those are the addresses a proxy on the same host or in the
+// same private network would connect from.
+type ProxyHeadersConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// TrustedCIDRs lists the networks a direct peer must be in for its
+	// X-Forwarded-*/Forwarded headers to be honored at all; a request from
+	// outside these ranges has its headers ignored, since anyone could set
+	// them.
+	TrustedCIDRs []string `yaml:"trusted_cidrs"`
+}
+
+// APIConfig controls HTTP-layer policy for cmd/server that isn't specific
+// to any one route, starting with CORS.
+type APIConfig struct {
+	CORS CORSConfig `yaml:"cors"`
+
+	// BindAddr is the address the HTTP(S) server listens on, e.g. ":8080".
+	// Defaults to Port, so entrypoints that only set PORT keep working
+	// unchanged.
+	BindAddr     string        `yaml:"bind_addr"`
+	ReadTimeout  time.Duration `yaml:"read_timeout"`
+	WriteTimeout time.Duration `yaml:"write_timeout"`
+	IdleTimeout  time.Duration `yaml:"idle_timeout"`
+
+	TLS TLSConfig `yaml:"tls"`
+}
+
+// TLSConfig selects at most one of two ways to serve HTTPS: a static
+// cert/key pair, or Let's Encrypt via autocert. Config.validate rejects
+// configuring both.
+type TLSConfig struct {
+	CertFile string         `yaml:"cert_file"`
+	KeyFile  string         `yaml:"key_file"`
+	Autocert AutocertConfig `yaml:"autocert"`
+}
+
+// AutocertConfig drives golang.org/x/crypto/acme/autocert: certificates for
+// Domains are obtained from Let's Encrypt on first handshake and cached in
+// CacheDir, with HTTP-01 challenges served on :80 alongside the HTTPS
+// listener on BindAddr.
+type AutocertConfig struct {
+	Enabled  bool     `yaml:"enabled"`
+	Domains  []string `yaml:"domains"`
+	CacheDir string   `yaml:"cache_dir"`
+	// Email is passed to Let's Encrypt for expiry/problem notifications.
+	Email string `yaml:"email"`
+}
+
+// CORSConfig drives middleware.CORS. AllowedOrigins entries may be "*", an
+// exact origin, or a wildcard subdomain like "*.example.com"; anything else
+// is rejected rather than echoed back, since echoing an unrecognized Origin
+// would defeat the allowlist.
+type CORSConfig struct {
+	AllowedOrigins   []string `yaml:"allowed_origins"`
+	AllowedMethods   []string `yaml:"allowed_methods"`
+	AllowedHeaders   []string `yaml:"allowed_headers"`
+	ExposedHeaders   []string `yaml:"exposed_headers"`
+	AllowCredentials bool     `yaml:"allow_credentials"`
+	// MaxAge is how long (e.g. "10m") a preflight response may be cached by
+	// the browser. An invalid value omits the Access-Control-Max-Age header.
+	MaxAge string `yaml:"max_age"`
+}
+
 // LoadFromFile loads configuration from a YAML file
 func LoadFromFile(filename string) (*Config, error) {
 	// Check if file exists
@@ -125,6 +431,11 @@ func LoadFromFile(filename string) (*Config, error) {
 
 // applyDefaults sets default values for missing configuration
 func (c *Config) applyDefaults() {
+	// Engine defaults
+	if c.Engine.Workers == 0 {
+		c.Engine.Workers = 4
+	}
+
 	// Monitoring defaults
 	if c.Monitoring.Sources.Certstream.URL == "" {
 		c.Monitoring.Sources.Certstream.URL = "wss://certstream.calidog.io/"
@@ -146,10 +457,33 @@ func (c *Config) applyDefaults() {
 		c.Rules.FaviconSimilarity.Threshold = 0.85
 	}
 
+	// Scenarios defaults
+	if c.Scenarios.Dir == "" {
+		c.Scenarios.Dir = "scenarios"
+	}
+
+	// Hub defaults
+	if c.Hub.CacheDir == "" {
+		c.Hub.CacheDir = "hub-cache"
+	}
+
+	// Decisions defaults
+	if c.Decisions.TTL == "" {
+		c.Decisions.TTL = "1h"
+	}
+
 	// Storage defaults
 	if c.Storage.Type == "" {
 		c.Storage.Type = "memory"
 	}
+	if c.Storage.Type == "sqlite" && c.Storage.DSN == "" {
+		c.Storage.DSN = "openbpl.db"
+	}
+
+	// Environment defaults
+	if c.Environment == "" {
+		c.Environment = "development"
+	}
 
 	// Logging defaults
 	if c.Logging.Level == "" {
@@ -163,18 +497,92 @@ func (c *Config) applyDefaults() {
 	if c.Enforcement.EmailAbuse.SMTP.Port == 0 {
 		c.Enforcement.EmailAbuse.SMTP.Port = 587
 	}
+
+	// LAPI publish defaults
+	if c.Enforcement.LAPIPublish.TTL == "" {
+		c.Enforcement.LAPIPublish.TTL = "1h"
+	}
+
+	// CORS defaults
+	if len(c.API.CORS.AllowedOrigins) == 0 {
+		c.API.CORS.AllowedOrigins = []string{"*"}
+	}
+	if len(c.API.CORS.AllowedMethods) == 0 {
+		c.API.CORS.AllowedMethods = []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"}
+	}
+	if len(c.API.CORS.AllowedHeaders) == 0 {
+		c.API.CORS.AllowedHeaders = []string{"Content-Type", "Authorization"}
+	}
+	if c.API.CORS.MaxAge == "" {
+		c.API.CORS.MaxAge = "10m"
+	}
+	if c.API.BindAddr == "" {
+		c.API.BindAddr = c.Port
+	}
+	if c.API.ReadTimeout == 0 {
+		c.API.ReadTimeout = 15 * time.Second
+	}
+	if c.API.WriteTimeout == 0 {
+		c.API.WriteTimeout = 15 * time.Second
+	}
+	if c.API.IdleTimeout == 0 {
+		c.API.IdleTimeout = 60 * time.Second
+	}
+	if c.API.TLS.Autocert.CacheDir == "" {
+		c.API.TLS.Autocert.CacheDir = "autocert-cache"
+	}
+
+	// Metrics defaults
+	if c.Metrics.Path == "" {
+		c.Metrics.Path = "/metrics"
+	}
+
+	// Access log defaults
+	if c.AccessLog.Format == "" {
+		c.AccessLog.Format = "combined"
+	}
+	if c.AccessLog.Output == "" {
+		c.AccessLog.Output = "stdout"
+	}
+	if c.AccessLog.MaxSizeMB == 0 {
+		c.AccessLog.MaxSizeMB = 100
+	}
+	if c.AccessLog.MaxBackups == 0 {
+		c.AccessLog.MaxBackups = 5
+	}
+
+	// Proxy headers defaults: loopback + RFC 1918 private ranges, so a
+	// proxy on the same host or the same private network is trusted out of
+	// the box, but nothing reachable over the public internet is.
+	if len(c.Security.ProxyHeaders.TrustedCIDRs) == 0 {
+		c.Security.ProxyHeaders.TrustedCIDRs = defaultTrustedCIDRs
+	}
+}
+
+// defaultTrustedCIDRs is the ProxyHeadersConfig.TrustedCIDRs default:
+// loopback plus the RFC 1918 private address ranges.
+var defaultTrustedCIDRs = []string{
+	"127.0.0.1/32",
+	"::1/128",
+	"10.0.0.0/8",
+	"172.16.0.0/12",
+	"192.168.0.0/16",
 }
 
 // validate checks if the configuration is valid
 func (c *Config) validate() error {
 	// Check storage type
 	validStorageTypes := map[string]bool{
-		"memory":   true,
-		"sqlite":   true,
-		"postgres": true,
+		"memory":            true,
+		"sqlite":            true,
+		"postgres":          true,
+		"embedded-postgres": true,
 	}
 	if !validStorageTypes[c.Storage.Type] {
-		return fmt.Errorf("invalid storage type: %s (must be: memory, sqlite, postgres)", c.Storage.Type)
+		return fmt.Errorf("invalid storage type: %s (must be: memory, sqlite, postgres, embedded-postgres)", c.Storage.Type)
+	}
+	if c.Storage.Type == "postgres" && c.Storage.DSN == "" {
+		return fmt.Errorf("storage dsn is required when storage type is postgres")
 	}
 
 	// Check logging level
@@ -205,6 +613,57 @@ func (c *Config) validate() error {
 		}
 	}
 
+	// Check LAPI publish configuration if enabled
+	if c.Enforcement.LAPIPublish.Enabled {
+		if c.Enforcement.LAPIPublish.URL == "" {
+			return fmt.Errorf("lapi_publish url is required when lapi_publish enforcement is enabled")
+		}
+		if c.Enforcement.LAPIPublish.APIKey == "" {
+			return fmt.Errorf("lapi_publish api_key is required when lapi_publish enforcement is enabled")
+		}
+	}
+
+	// Check TLS configuration: at most one of static cert/key or autocert,
+	// and autocert needs at least one domain to request a cert for.
+	staticTLS := c.API.TLS.CertFile != "" || c.API.TLS.KeyFile != ""
+	if staticTLS && c.API.TLS.Autocert.Enabled {
+		return fmt.Errorf("api.tls: cannot enable both a static cert_file/key_file and autocert")
+	}
+	if c.API.TLS.Autocert.Enabled && len(c.API.TLS.Autocert.Domains) == 0 {
+		return fmt.Errorf("api.tls.autocert requires at least one domain")
+	}
+
+	// Check access log configuration
+	validAccessLogFormats := map[string]bool{"text": true, "combined": true, "json": true}
+	if c.AccessLog.Enabled && !validAccessLogFormats[c.AccessLog.Format] {
+		return fmt.Errorf("invalid access log format: %s (must be: text, combined, json)", c.AccessLog.Format)
+	}
+	validAccessLogOutputs := map[string]bool{"stdout": true, "stderr": true, "file": true}
+	if c.AccessLog.Enabled && !validAccessLogOutputs[c.AccessLog.Output] {
+		return fmt.Errorf("invalid access log output: %s (must be: stdout, stderr, file)", c.AccessLog.Output)
+	}
+	if c.AccessLog.Enabled && c.AccessLog.Output == "file" && c.AccessLog.Path == "" {
+		return fmt.Errorf("access_log path is required when access_log output is file")
+	}
+
+	// Check proxy headers trusted CIDRs
+	for _, cidr := range c.Security.ProxyHeaders.TrustedCIDRs {
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			return fmt.Errorf("invalid security.proxy_headers.trusted_cidrs entry %q: %w", cidr, err)
+		}
+	}
+
+	// A wildcard origin can't be combined with credentialed requests: the
+	// browser would refuse to expose the response, and reflecting "*" back
+	// as Access-Control-Allow-Origin with credentials on is itself unsafe.
+	if c.API.CORS.AllowCredentials {
+		for _, origin := range c.API.CORS.AllowedOrigins {
+			if origin == "*" {
+				return fmt.Errorf("api.cors.allow_credentials cannot be used with a wildcard (\"*\") allowed origin")
+			}
+		}
+	}
+
 	return nil
 }
 
@@ -218,6 +677,10 @@ func CreateSampleConfig(filename string) error {
 	sampleConfig := `# OpenBPL Configuration
 # This is a sample configuration for the OpenBPL monitoring system
 
+# Worker pool that processes events and enforcement jobs
+engine:
+  workers: 4
+
 # Monitoring configuration
 monitoring:
   sources:
@@ -226,10 +689,25 @@ monitoring:
       url: "wss://certstream.calidog.io/"
       keywords:
         - "paypal"
-        - "amazon" 
+        - "amazon"
         - "microsoft"
         - "apple"
         - "google"
+    additional:
+      - type: "dnstwist"
+        enabled: false
+        params:
+          keywords: ["paypal", "amazon"]
+          tlds: ["com", "net"]
+          interval: "1h"
+      # Runs this instance as a detection-only agent that pulls the
+      # block/allow list from a central LAPI instead of (or in addition to)
+      # detecting locally.
+      - type: "lapi"
+        enabled: false
+        params:
+          url: "https://lapi.example.com"
+          api_key: "${LAPI_API_KEY}"
 
 # Enrichment settings  
 enrichment:
@@ -253,6 +731,26 @@ rules:
       apple: "https://www.apple.com/favicon.ico"
       google: "https://www.google.com/favicon.ico"
 
+# Scenario engine (declarative detection rules, see scenarios/*.yaml)
+scenarios:
+  enabled: false
+  dir: "scenarios"
+
+# Community hub of installable detector configs (favicon sets, keyword
+# lists, rules, brand profiles). See "openbpl hub install <name>".
+hub:
+  enabled: false
+  cache_dir: "hub-cache"
+  index_url: "https://hub.openbpl.org/hub-index.yaml"
+  # Hex-encoded ed25519 public key used to verify hub-index.yaml against the
+  # detached signature published alongside it at index_url + ".sig".
+  public_key: ""
+
+# LAPI-style decisions published for bouncers to subscribe to
+decisions:
+  enabled: false
+  ttl: "1h"
+
 # Enforcement actions
 enforcement:
   email_abuse:
@@ -265,18 +763,93 @@ enforcement:
     from: "OpenBPL <alerts@yourdomain.com>"
   logger:
     enabled: true
+  # Push confirmed threats to a central LAPI instead of (or in addition to)
+  # acting on them locally. Register this machine first against the peer's
+  # POST /api/v1/machines/register to obtain api_key.
+  lapi_publish:
+    enabled: false
+    url: "https://lapi.example.com"
+    api_key: "${LAPI_API_KEY}"
+    ttl: "1h"
 
 # Storage configuration
 storage:
-  type: "memory"  # Options: memory, sqlite, postgres
-  
+  type: "memory"  # Options: memory, sqlite, postgres, embedded-postgres
+  dsn: ""         # sqlite file path, or postgres connection URL; unused for embedded-postgres
+
 # Logging
 logging:
   level: "info"
   format: "text"
 
+# Per-request HTTP access log (NCSA/Apache Combined Log Format by default).
+# Distinct from the application-level "logging" above. When enabled, the
+# plain request-line Logger middleware steps aside to avoid duplicate lines.
+access_log:
+  enabled: false
+  format: "combined"  # text, combined, or json
+  output: "stdout"    # stdout, stderr, or file
+  path: ""            # required when output is "file"
+  max_size_mb: 100
+  max_age_days: 28
+  max_backups: 5
+
+# HTTP API policy
+api:
+  bind_addr: ":8080"
+  read_timeout: "15s"
+  write_timeout: "15s"
+  idle_timeout: "60s"
+  # TLS is optional: leave both cert_file/key_file and autocert unset to
+  # serve plain HTTP, e.g. behind a TLS-terminating load balancer.
+  tls:
+    cert_file: ""
+    key_file: ""
+    autocert:
+      enabled: false
+      domains: []
+      cache_dir: "autocert-cache"
+      email: ""
+  cors:
+    allowed_origins:
+      - "*"
+    allowed_methods:
+      - "GET"
+      - "POST"
+      - "PUT"
+      - "DELETE"
+      - "OPTIONS"
+    allowed_headers:
+      - "Content-Type"
+      - "Authorization"
+    exposed_headers: []
+    allow_credentials: false
+    max_age: "10m"
+
+# Prometheus metrics endpoint
+metrics:
+  enabled: true
+  path: "/metrics"
+  bind_addr: ""  # e.g. ":9090" to serve metrics on a separate, internal-only listener
+
+security:
+  # Recover the true client IP/scheme/host from X-Forwarded-For,
+  # X-Real-IP, Forwarded, and X-Forwarded-Proto/Host, but only when the
+  # direct peer is in trusted_cidrs - anyone else's headers are ignored.
+  proxy_headers:
+    enabled: false
+    trusted_cidrs:
+      - "127.0.0.1/32"
+      - "::1/128"
+      - "10.0.0.0/8"
+      - "172.16.0.0/12"
+      - "192.168.0.0/16"
+
 # Run in dry-run mode (no enforcement actions will be taken)
 dry_run: false
+
+# development or production; controls structured log output format
+environment: "development"
 `
 
 	if err := os.WriteFile(filename, []byte(sampleConfig), 0644); err != nil {
@@ -29,6 +29,30 @@ func TestLoad(t *testing.T) {
 		if !cfg.IsDevelopment() {
 			t.Error("Expected development mode")
 		}
+
+		if len(cfg.API.CORS.AllowedOrigins) != 1 || cfg.API.CORS.AllowedOrigins[0] != "*" {
+			t.Errorf("Expected default CORS allowed origins [*], got %v", cfg.API.CORS.AllowedOrigins)
+		}
+	})
+
+	t.Run("loads CORS allowed origins from env", func(t *testing.T) {
+		cleanupEnv := setupCleanEnv()
+		defer cleanupEnv()
+
+		os.Setenv("CORS_ALLOWED_ORIGINS", "https://a.example.com,https://b.example.com")
+
+		cfg := Load()
+
+		want := []string{"https://a.example.com", "https://b.example.com"}
+		if len(cfg.API.CORS.AllowedOrigins) != len(want) {
+			t.Fatalf("expected %v, got %v", want, cfg.API.CORS.AllowedOrigins)
+		}
+		for i, origin := range want {
+			if cfg.API.CORS.AllowedOrigins[i] != origin {
+				t.Errorf("expected %v, got %v", want, cfg.API.CORS.AllowedOrigins)
+				break
+			}
+		}
 	})
 
 	t.Run("loads environment variables", func(t *testing.T) {
@@ -80,10 +104,11 @@ func TestLoad(t *testing.T) {
 func setupCleanEnv() func() {
 	// Store original values
 	originalVars := map[string]string{
-		"PORT":        os.Getenv("PORT"),
-		"JWT_SECRET":  os.Getenv("JWT_SECRET"),
-		"JWT_EXPIRY":  os.Getenv("JWT_EXPIRY"),
-		"ENVIRONMENT": os.Getenv("ENVIRONMENT"),
+		"PORT":                 os.Getenv("PORT"),
+		"JWT_SECRET":           os.Getenv("JWT_SECRET"),
+		"JWT_EXPIRY":           os.Getenv("JWT_EXPIRY"),
+		"ENVIRONMENT":          os.Getenv("ENVIRONMENT"),
+		"CORS_ALLOWED_ORIGINS": os.Getenv("CORS_ALLOWED_ORIGINS"),
 	}
 
 	// Clear all config-related env vars
@@ -0,0 +1,150 @@
+package config
+
+import (
+	"context"
+	"log"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// reloadDebounce coalesces the burst of write events a single save can
+// produce (editors often write-then-rename, or write in several chunks)
+// into one reload.
+const reloadDebounce = 200 * time.Millisecond
+
+// Manager owns a *Config loaded from a YAML file and, once Watch is
+// running, keeps it current as that file changes on disk. Get is safe to
+// call concurrently with a reload; callers that need a consistent view
+// across several fields should call Get once and read from the result,
+// since a reload can swap in a new *Config between two separate Get calls.
+type Manager struct {
+	current atomic.Pointer[Config]
+
+	mu          sync.Mutex
+	subscribers []func(old, new *Config)
+}
+
+// NewManager loads filename once via LoadFromFile and returns a Manager
+// wrapping it. Call Watch to keep it current as the file changes.
+func NewManager(filename string) (*Manager, error) {
+	cfg, err := LoadFromFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &Manager{}
+	m.current.Store(cfg)
+	return m, nil
+}
+
+// Get returns the most recently loaded Config.
+func (m *Manager) Get() *Config {
+	return m.current.Load()
+}
+
+// Subscribe registers fn to be called, with the previous and new Config,
+// every time Watch successfully reloads filename. fn is not called for the
+// Config NewManager loaded initially, only for reloads. Subscribers run
+// synchronously on the watch goroutine in registration order, so a slow or
+// blocking subscriber delays later ones from seeing the update.
+func (m *Manager) Subscribe(fn func(old, new *Config)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.subscribers = append(m.subscribers, fn)
+}
+
+// Watch starts an fsnotify watch on filename's directory (watching the
+// directory rather than the file itself survives editors that replace the
+// file via rename-on-save) and reloads on every write/create event
+// affecting filename, debounced by reloadDebounce. It returns once the
+// watch is established; the watch itself runs until ctx is canceled. A
+// reload that fails to parse or validate is logged and discarded - the
+// previous Config is never replaced with an invalid one.
+func (m *Manager) Watch(ctx context.Context, filename string) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(filename)
+	if err := watcher.Add(dir); err != nil {
+		watcher.Close()
+		return err
+	}
+
+	go m.watchLoop(ctx, watcher, filename)
+	return nil
+}
+
+func (m *Manager) watchLoop(ctx context.Context, watcher *fsnotify.Watcher, filename string) {
+	defer watcher.Close()
+
+	var debounce *time.Timer
+	reload := make(chan struct{}, 1)
+
+	for {
+		select {
+		case <-ctx.Done():
+			if debounce != nil {
+				debounce.Stop()
+			}
+			return
+
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+			if filepath.Clean(event.Name) != filepath.Clean(filename) {
+				continue
+			}
+			if event.Op&(fsnotify.Write|fsnotify.Create) == 0 {
+				continue
+			}
+
+			if debounce == nil {
+				debounce = time.AfterFunc(reloadDebounce, func() {
+					select {
+					case reload <- struct{}{}:
+					default:
+					}
+				})
+			} else {
+				debounce.Reset(reloadDebounce)
+			}
+
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+			log.Printf("⚠️ config watch error: %v", err)
+
+		case <-reload:
+			m.reload(filename)
+		}
+	}
+}
+
+// reload re-reads filename and, if it parses and validates, swaps it in
+// and notifies subscribers; otherwise it logs the error and leaves the
+// current Config untouched.
+func (m *Manager) reload(filename string) {
+	next, err := LoadFromFile(filename)
+	if err != nil {
+		log.Printf("⚠️ config reload failed, keeping previous configuration: %v", err)
+		return
+	}
+
+	prev := m.current.Swap(next)
+
+	m.mu.Lock()
+	subscribers := append([]func(old, new *Config){}, m.subscribers...)
+	m.mu.Unlock()
+
+	for _, fn := range subscribers {
+		fn(prev, next)
+	}
+}
@@ -0,0 +1,78 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeTestConfig(t *testing.T, path, keywords string) {
+	t.Helper()
+	yaml := "monitoring:\n  sources:\n    certstream:\n      enabled: true\n      keywords: [" + keywords + "]\n"
+	if err := os.WriteFile(path, []byte(yaml), 0644); err != nil {
+		t.Fatalf("failed to write test config: %v", err)
+	}
+}
+
+func TestManagerWatchReloadsOnWrite(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "openbpl.yaml")
+	writeTestConfig(t, path, `"paypal"`)
+
+	m, err := NewManager(path)
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	if got := m.Get().Monitoring.Sources.Certstream.Keywords; len(got) != 1 || got[0] != "paypal" {
+		t.Fatalf("expected initial keywords [paypal], got %v", got)
+	}
+
+	reloaded := make(chan *Config, 1)
+	m.Subscribe(func(old, new *Config) {
+		reloaded <- new
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := m.Watch(ctx, path); err != nil {
+		t.Fatalf("Watch failed: %v", err)
+	}
+
+	writeTestConfig(t, path, `"paypal", "amazon"`)
+
+	select {
+	case next := <-reloaded:
+		if got := next.Monitoring.Sources.Certstream.Keywords; len(got) != 2 {
+			t.Errorf("expected reloaded keywords [paypal amazon], got %v", got)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for config reload")
+	}
+
+	if got := m.Get().Monitoring.Sources.Certstream.Keywords; len(got) != 2 {
+		t.Errorf("expected Get() to reflect reload, got %v", got)
+	}
+}
+
+func TestManagerReloadKeepsPreviousOnInvalidConfig(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "openbpl.yaml")
+	writeTestConfig(t, path, `"paypal"`)
+
+	m, err := NewManager(path)
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+
+	if err := os.WriteFile(path, []byte("storage:\n  type: \"not-a-real-type\"\n"), 0644); err != nil {
+		t.Fatalf("failed to write invalid config: %v", err)
+	}
+
+	m.reload(path)
+
+	if got := m.Get().Monitoring.Sources.Certstream.Keywords; len(got) != 1 || got[0] != "paypal" {
+		t.Errorf("expected previous config retained after invalid reload, got %v", got)
+	}
+}
@@ -8,6 +8,8 @@ import (
 	"time"
 
 	_ "github.com/lib/pq"
+
+	"openbpl/internal/database/migrations"
 )
 
 type DB struct {
@@ -47,3 +49,33 @@ func (db *DB) HealthCheck() error {
 
 	return db.PingContext(ctx)
 }
+
+// Migrate applies every pending schema migration. golang-migrate itself is
+// synchronous; running it on a goroutine lets ctx cancellation still cut a
+// caller loose instead of blocking until it finishes.
+func (db *DB) Migrate(ctx context.Context) error {
+	return runMigration(ctx, func() error { return migrations.Up(db.DB) })
+}
+
+// MigrateDown rolls back up to steps schema migrations.
+func (db *DB) MigrateDown(ctx context.Context, steps int) error {
+	return runMigration(ctx, func() error { return migrations.Down(db.DB, steps) })
+}
+
+// MigrationVersion reports the schema's current migration version and
+// whether it was left dirty by a previous failed migration.
+func (db *DB) MigrationVersion() (uint, bool, error) {
+	return migrations.Version(db.DB)
+}
+
+func runMigration(ctx context.Context, fn func() error) error {
+	done := make(chan error, 1)
+	go func() { done <- fn() }()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case err := <-done:
+		return err
+	}
+}
@@ -0,0 +1,102 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	embeddedpostgres "github.com/fergusstrange/embedded-postgres"
+)
+
+// EmbeddedOptions configures StartEmbedded.
+type EmbeddedOptions struct {
+	Port     uint32
+	Username string
+	Password string
+	Database string
+	// SchemaSQL, if set, is executed once the embedded server is reachable
+	// - e.g. a package's `CREATE TABLE IF NOT EXISTS ...` schema string -
+	// so callers get a ready-to-use database instead of an empty one.
+	SchemaSQL string
+}
+
+// DefaultEmbeddedOptions returns dev/test-friendly defaults: a port
+// unlikely to collide with a real local Postgres, generic credentials, and
+// an "openbpl" database.
+func DefaultEmbeddedOptions() EmbeddedOptions {
+	return EmbeddedOptions{
+		Port:     15432,
+		Username: "openbpl",
+		Password: "openbpl",
+		Database: "openbpl",
+	}
+}
+
+// StartEmbedded downloads (on first use), extracts, and starts a local
+// Postgres binary under $XDG_CACHE_HOME/openbpl, so development and tests
+// get a real Postgres without any external service. The returned cleanup
+// func stops the embedded server and closes db; callers should defer it as
+// soon as StartEmbedded returns a nil error.
+func StartEmbedded(ctx context.Context, opts EmbeddedOptions) (db *DB, cleanup func(), err error) {
+	cacheDir, err := embeddedCacheDir()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to resolve embedded postgres cache dir: %w", err)
+	}
+
+	pgConfig := embeddedpostgres.DefaultConfig().
+		Username(opts.Username).
+		Password(opts.Password).
+		Database(opts.Database).
+		Port(opts.Port).
+		BinariesPath(filepath.Join(cacheDir, "binaries")).
+		CachePath(filepath.Join(cacheDir, "cache")).
+		RuntimePath(filepath.Join(cacheDir, "runtime"))
+
+	postgres := embeddedpostgres.NewDatabase(pgConfig)
+	if err := postgres.Start(); err != nil {
+		return nil, nil, fmt.Errorf("failed to start embedded postgres: %w", err)
+	}
+
+	databaseURL := fmt.Sprintf("postgres://%s:%s@localhost:%d/%s?sslmode=disable",
+		opts.Username, opts.Password, opts.Port, opts.Database)
+
+	db, err = Connect(databaseURL)
+	if err != nil {
+		postgres.Stop()
+		return nil, nil, fmt.Errorf("failed to connect to embedded postgres: %w", err)
+	}
+
+	if opts.SchemaSQL != "" {
+		if _, err := db.Exec(opts.SchemaSQL); err != nil {
+			db.Close()
+			postgres.Stop()
+			return nil, nil, fmt.Errorf("failed to apply schema to embedded postgres: %w", err)
+		}
+	}
+
+	cleanup = func() {
+		db.Close()
+		if err := postgres.Stop(); err != nil {
+			log.Printf("⚠️ failed to stop embedded postgres: %v", err)
+		}
+	}
+
+	return db, cleanup, nil
+}
+
+// embeddedCacheDir returns $XDG_CACHE_HOME/openbpl/embedded-postgres,
+// falling back to $HOME/.cache per the XDG base directory spec when
+// XDG_CACHE_HOME is unset.
+func embeddedCacheDir() (string, error) {
+	base := os.Getenv("XDG_CACHE_HOME")
+	if base == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		base = filepath.Join(home, ".cache")
+	}
+	return filepath.Join(base, "openbpl", "embedded-postgres"), nil
+}
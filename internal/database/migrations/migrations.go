@@ -0,0 +1,114 @@
+// Package migrations embeds the SQL schema migrations for the
+// application's users/threats tables and drives them via golang-migrate.
+// This is separate from pkg/core's storage schemas (events, detections,
+// jobs), which each storage backend still manages with its own
+// CREATE-TABLE-IF-NOT-EXISTS on connect.
+package migrations
+
+import (
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database/postgres"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+)
+
+//go:embed *.sql
+var migrationFS embed.FS
+
+// newMigrate builds a migrate.Migrate reading the embedded SQL files and
+// writing to db's schema_migrations table.
+func newMigrate(db *sql.DB) (*migrate.Migrate, error) {
+	source, err := iofs.New(migrationFS, ".")
+	if err != nil {
+		return nil, fmt.Errorf("failed to load embedded migrations: %w", err)
+	}
+
+	driver, err := postgres.WithInstance(db, &postgres.Config{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create postgres migration driver: %w", err)
+	}
+
+	m, err := migrate.NewWithInstance("iofs", source, "postgres", driver)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize migrator: %w", err)
+	}
+	return m, nil
+}
+
+// Up applies every migration that hasn't run yet. A schema that's already
+// up to date is not an error.
+func Up(db *sql.DB) error {
+	m, err := newMigrate(db)
+	if err != nil {
+		return err
+	}
+	if err := m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return err
+	}
+	return nil
+}
+
+// Down rolls back up to steps migrations. Rolling back past the earliest
+// migration is not an error; it just leaves the schema at version 0.
+func Down(db *sql.DB, steps int) error {
+	m, err := newMigrate(db)
+	if err != nil {
+		return err
+	}
+	if err := m.Steps(-steps); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		var short migrate.ErrShortLimit
+		if errors.As(err, &short) {
+			return nil
+		}
+		return err
+	}
+	return nil
+}
+
+// Version reports the schema's current migration version and whether a
+// previous migration left it dirty. A database that has never been
+// migrated reports (0, false, nil).
+func Version(db *sql.DB) (uint, bool, error) {
+	m, err := newMigrate(db)
+	if err != nil {
+		return 0, false, err
+	}
+
+	version, dirty, err := m.Version()
+	if errors.Is(err, migrate.ErrNilVersion) {
+		return 0, false, nil
+	}
+	return version, dirty, err
+}
+
+// LatestVersion returns the highest migration version embedded in this
+// binary, parsed off the leading digits of each migration's filename.
+func LatestVersion() (uint, error) {
+	entries, err := migrationFS.ReadDir(".")
+	if err != nil {
+		return 0, fmt.Errorf("failed to read embedded migrations: %w", err)
+	}
+
+	var latest uint
+	for _, entry := range entries {
+		name := entry.Name()
+		idx := strings.IndexByte(name, '_')
+		if idx < 0 {
+			continue
+		}
+		version, err := strconv.ParseUint(name[:idx], 10, 64)
+		if err != nil {
+			continue
+		}
+		if uint(version) > latest {
+			latest = uint(version)
+		}
+	}
+	return latest, nil
+}
@@ -0,0 +1,162 @@
+// Package diagnostics builds a redacted support bundle - a zip archive of
+// runtime stats, a goroutine dump, resolved config, database health, and
+// component status - shared by the CLI's "openbpl diagnostics" subcommand
+// and the HTTP server's /api/v1/diagnostics endpoint, so the two surfaces
+// can't drift apart.
+package diagnostics
+
+import (
+	"archive/zip"
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"runtime"
+	"runtime/pprof"
+	"time"
+)
+
+// Info is everything a caller can supply about itself. Every field is
+// optional; Bundle skips sections it has nothing to report for.
+type Info struct {
+	// Component names the process producing the bundle, e.g. "cli" or
+	// "server".
+	Component string
+	// Version, Commit, and BuildTime are the build identifiers baked
+	// into the binary.
+	Version   string
+	Commit    string
+	BuildTime string
+	// Config is the resolved configuration, already rendered to text
+	// (e.g. YAML) with secrets masked by the caller.
+	Config string
+	// DB, if set, contributes a connection-pool snapshot and a ping-based
+	// health check to the bundle.
+	DB *sql.DB
+	// ComponentStatus reports arbitrary named component health, e.g.
+	// {"certstream": "connected", "storage": "postgres"}.
+	ComponentStatus map[string]string
+	// LogLines are the last N application log lines to include verbatim.
+	LogLines []string
+}
+
+// Bundle renders info into a zip archive containing meta.json (version,
+// OS/arch, generated_at), runtime.json, goroutines.txt, and - when
+// supplied - config.yaml, db.json, components.json, and recent.log.
+func Bundle(info Info) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+
+	if err := writeJSONEntry(zw, "meta.json", map[string]interface{}{
+		"component":    info.Component,
+		"version":      info.Version,
+		"commit":       info.Commit,
+		"build_time":   info.BuildTime,
+		"os":           runtime.GOOS,
+		"arch":         runtime.GOARCH,
+		"go_version":   runtime.Version(),
+		"generated_at": time.Now().UTC(),
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := writeJSONEntry(zw, "runtime.json", runtimeStats()); err != nil {
+		return nil, err
+	}
+
+	if err := writeGoroutineDump(zw); err != nil {
+		return nil, err
+	}
+
+	if info.Config != "" {
+		if err := writeTextEntry(zw, "config.yaml", info.Config); err != nil {
+			return nil, err
+		}
+	}
+
+	if info.DB != nil {
+		if err := writeJSONEntry(zw, "db.json", dbDiagnostics(info.DB)); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(info.ComponentStatus) > 0 {
+		if err := writeJSONEntry(zw, "components.json", info.ComponentStatus); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(info.LogLines) > 0 {
+		var logs bytes.Buffer
+		for _, line := range info.LogLines {
+			logs.WriteString(line)
+			logs.WriteByte('\n')
+		}
+		if err := writeTextEntry(zw, "recent.log", logs.String()); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("diagnostics: failed to finalize bundle: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func runtimeStats() map[string]interface{} {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+	return map[string]interface{}{
+		"goroutines": runtime.NumGoroutine(),
+		"num_cpu":    runtime.NumCPU(),
+		"heap_alloc": mem.HeapAlloc,
+		"heap_sys":   mem.HeapSys,
+		"num_gc":     mem.NumGC,
+	}
+}
+
+func dbDiagnostics(db *sql.DB) map[string]interface{} {
+	stats := db.Stats()
+	return map[string]interface{}{
+		"open_connections": stats.OpenConnections,
+		"in_use":           stats.InUse,
+		"idle":             stats.Idle,
+		"wait_count":       stats.WaitCount,
+		"healthy":          db.Ping() == nil,
+	}
+}
+
+func writeGoroutineDump(zw *zip.Writer) error {
+	w, err := zw.Create("goroutines.txt")
+	if err != nil {
+		return fmt.Errorf("diagnostics: failed to add goroutines.txt: %w", err)
+	}
+	if err := pprof.Lookup("goroutine").WriteTo(w, 1); err != nil {
+		return fmt.Errorf("diagnostics: failed to write goroutine dump: %w", err)
+	}
+	return nil
+}
+
+func writeJSONEntry(zw *zip.Writer, name string, v interface{}) error {
+	w, err := zw.Create(name)
+	if err != nil {
+		return fmt.Errorf("diagnostics: failed to add %s: %w", name, err)
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return fmt.Errorf("diagnostics: failed to write %s: %w", name, err)
+	}
+	return nil
+}
+
+func writeTextEntry(zw *zip.Writer, name, content string) error {
+	w, err := zw.Create(name)
+	if err != nil {
+		return fmt.Errorf("diagnostics: failed to add %s: %w", name, err)
+	}
+	if _, err := w.Write([]byte(content)); err != nil {
+		return fmt.Errorf("diagnostics: failed to write %s: %w", name, err)
+	}
+	return nil
+}
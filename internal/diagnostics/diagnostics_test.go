@@ -0,0 +1,99 @@
+package diagnostics
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestBundleIncludesCoreEntries(t *testing.T) {
+	data, err := Bundle(Info{Component: "test", Version: "1.2.3"})
+	if err != nil {
+		t.Fatalf("Bundle failed: %v", err)
+	}
+
+	names := zipEntryNames(t, data)
+	for _, want := range []string{"meta.json", "runtime.json", "goroutines.txt"} {
+		if !names[want] {
+			t.Errorf("Expected bundle to contain %s, got entries %v", want, names)
+		}
+	}
+}
+
+func TestBundleOmitsOptionalSectionsWhenUnset(t *testing.T) {
+	data, err := Bundle(Info{Component: "test"})
+	if err != nil {
+		t.Fatalf("Bundle failed: %v", err)
+	}
+
+	names := zipEntryNames(t, data)
+	for _, absent := range []string{"config.yaml", "db.json", "components.json", "recent.log"} {
+		if names[absent] {
+			t.Errorf("Expected bundle to omit %s when not supplied", absent)
+		}
+	}
+}
+
+func TestBundleIncludesSuppliedSections(t *testing.T) {
+	data, err := Bundle(Info{
+		Component:       "test",
+		Config:          "storage:\n  type: memory\n",
+		ComponentStatus: map[string]string{"certstream": "connected"},
+		LogLines:        []string{"line one", "line two"},
+	})
+	if err != nil {
+		t.Fatalf("Bundle failed: %v", err)
+	}
+
+	names := zipEntryNames(t, data)
+	for _, want := range []string{"config.yaml", "components.json", "recent.log"} {
+		if !names[want] {
+			t.Errorf("Expected bundle to contain %s, got entries %v", want, names)
+		}
+	}
+
+	meta := readJSONEntry(t, data, "meta.json")
+	if meta["component"] != "test" {
+		t.Errorf("Expected meta.component 'test', got %v", meta["component"])
+	}
+}
+
+func zipEntryNames(t *testing.T, data []byte) map[string]bool {
+	t.Helper()
+	r, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("Failed to read zip: %v", err)
+	}
+	names := make(map[string]bool, len(r.File))
+	for _, f := range r.File {
+		names[f.Name] = true
+	}
+	return names
+}
+
+func readJSONEntry(t *testing.T, data []byte, name string) map[string]interface{} {
+	t.Helper()
+	r, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("Failed to read zip: %v", err)
+	}
+	for _, f := range r.File {
+		if f.Name != name {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatalf("Failed to open %s: %v", name, err)
+		}
+		defer rc.Close()
+
+		var v map[string]interface{}
+		if err := json.NewDecoder(rc).Decode(&v); err != nil {
+			t.Fatalf("Failed to decode %s: %v", name, err)
+		}
+		return v
+	}
+	t.Fatalf("Entry %s not found", name)
+	return nil
+}
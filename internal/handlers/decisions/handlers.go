@@ -0,0 +1,208 @@
+package decisions
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// streamTimeout bounds how long a single GET /decisions/stream request waits
+// for new events before returning an empty body (the bouncer is expected to
+// call the endpoint again, long-poll style).
+const streamTimeout = 30 * time.Second
+
+// Handlers exposes the LAPI-style decisions endpoints over HTTP.
+type Handlers struct {
+	Store *Store
+
+	// Machines authenticates agents pushing decisions (POST /decisions) and
+	// registering (POST /machines/register). Nil disables both, leaving
+	// only the bouncer-facing List/Stream endpoints.
+	Machines *MachineRegistry
+
+	// DefaultTTL is applied to a pushed decision that doesn't set its own
+	// TTL.
+	DefaultTTL time.Duration
+}
+
+// NewHandlers creates handlers backed by store, authenticating pushed
+// decisions and machine registration against machines (which may be nil to
+// disable the agent-facing endpoints), and defaulting a pushed decision's
+// TTL to defaultTTL when it doesn't specify its own.
+func NewHandlers(store *Store, machines *MachineRegistry, defaultTTL time.Duration) *Handlers {
+	return &Handlers{Store: store, Machines: machines, DefaultTTL: defaultTTL}
+}
+
+// List handles GET /api/v1/decisions?scope=domain&value=example.com,
+// returning the currently active decisions matching the given filters.
+func (h *Handlers) List(w http.ResponseWriter, r *http.Request) {
+	scope := r.URL.Query().Get("scope")
+	value := r.URL.Query().Get("value")
+
+	decisions := h.Store.List(scope, value)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"decisions": decisions,
+		"count":     len(decisions),
+	})
+}
+
+// Stream handles GET /api/v1/decisions/stream?startup=true&since=<seq>.
+// With startup=true it replays the store's retained change log; otherwise it waits (long
+// polling, up to streamTimeout) for events after the since cursor and
+// writes them as newline-delimited JSON, one Event per line.
+func (h *Handlers) Stream(w http.ResponseWriter, r *http.Request) {
+	since := int64(0)
+	if r.URL.Query().Get("startup") != "true" {
+		since = parseSince(r.URL.Query().Get("since"))
+	}
+
+	events, latest, wait := h.Store.Since(since)
+	if len(events) == 0 {
+		select {
+		case <-wait:
+			events, latest, _ = h.Store.Since(since)
+		case <-time.After(streamTimeout):
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.Header().Set("X-Decision-Cursor", strconv.FormatInt(latest, 10))
+	w.WriteHeader(http.StatusOK)
+
+	encoder := json.NewEncoder(w)
+	for _, event := range events {
+		if err := encoder.Encode(event); err != nil {
+			return
+		}
+	}
+}
+
+// createRequest is the body of POST /api/v1/decisions: a confirmed
+// detection an agent wants published for other agents to subscribe to.
+type createRequest struct {
+	Scope    string `json:"scope"`
+	Value    string `json:"value"`
+	Type     string `json:"type"`
+	Scenario string `json:"scenario"`
+	// TTL overrides DefaultTTL for this decision, e.g. "2h". Optional.
+	TTL string `json:"ttl"`
+}
+
+// Create handles POST /api/v1/decisions, publishing a decision pushed by an
+// authenticated agent (see MachineRegistry.RequireMachineAuth). The
+// decision's Origin is set to the pushing machine's name, and it expires
+// after req.TTL, falling back to h.DefaultTTL.
+func (h *Handlers) Create(w http.ResponseWriter, r *http.Request) {
+	if h.Machines == nil {
+		http.Error(w, "machine endpoints are disabled", http.StatusNotFound)
+		return
+	}
+
+	var req createRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if req.Scope == "" || req.Value == "" {
+		http.Error(w, "scope and value are required", http.StatusBadRequest)
+		return
+	}
+
+	ttl := h.DefaultTTL
+	if req.TTL != "" {
+		parsed, err := time.ParseDuration(req.TTL)
+		if err != nil {
+			http.Error(w, "Invalid ttl: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		ttl = parsed
+	}
+
+	origin := "agent"
+	if m, ok := MachineFromContext(r.Context()); ok {
+		origin = m.Name
+	}
+
+	now := time.Now()
+	decision := h.Store.Add(Decision{
+		Scope:     req.Scope,
+		Value:     req.Value,
+		Type:      req.Type,
+		Scenario:  req.Scenario,
+		Origin:    origin,
+		CreatedAt: now,
+		ExpiresAt: now.Add(ttl),
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(decision)
+}
+
+// registerRequest is the body of POST /api/v1/machines/register.
+type registerRequest struct {
+	Name string `json:"name"`
+}
+
+// Register handles POST /api/v1/machines/register, issuing a new machine
+// identity and API key that the caller should use as its "Bearer <api_key>"
+// credential against Create.
+func (h *Handlers) Register(w http.ResponseWriter, r *http.Request) {
+	if h.Machines == nil {
+		http.Error(w, "machine endpoints are disabled", http.StatusNotFound)
+		return
+	}
+
+	var req registerRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if req.Name == "" {
+		http.Error(w, "name is required", http.StatusBadRequest)
+		return
+	}
+
+	machine, err := h.Machines.Register(req.Name)
+	if err != nil {
+		http.Error(w, "Failed to register machine: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(machine)
+}
+
+// Health handles GET /api/v1/decisions/health, reporting basic liveness
+// stats for this LAPI: how many decisions are active and how many machines
+// are registered, so an operator (or a peer LAPISource) can confirm it's up
+// before relying on it.
+func (h *Handlers) Health(w http.ResponseWriter, r *http.Request) {
+	machines := 0
+	if h.Machines != nil {
+		machines = len(h.Machines.List())
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"status":    "ok",
+		"decisions": len(h.Store.List("", "")),
+		"machines":  machines,
+	})
+}
+
+func parseSince(raw string) int64 {
+	since, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil {
+		return 0
+	}
+	return since
+}
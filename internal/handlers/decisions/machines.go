@@ -0,0 +1,138 @@
+package decisions
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// machineContextKey is the context key RequireMachineAuth stores the
+// authenticated Machine under, so downstream handlers (e.g. Create) can
+// attribute a pushed decision to its origin machine.
+type machineContextKey struct{}
+
+// MachineFromContext returns the Machine authenticated by RequireMachineAuth
+// for this request, if any.
+func MachineFromContext(ctx context.Context) (Machine, bool) {
+	m, ok := ctx.Value(machineContextKey{}).(Machine)
+	return m, ok
+}
+
+// Machine is an OpenBPL agent registered with this LAPI, identified by its
+// own API key - distinct from the shared bouncer bearer token that guards
+// List/Stream, since each agent needs to be individually revocable.
+type Machine struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	APIKey    string    `json:"api_key"`
+	CreatedAt time.Time `json:"created_at"`
+	LastSeen  time.Time `json:"last_seen"`
+}
+
+// MachineRegistry issues and authenticates per-agent API keys, tracking each
+// machine's last-seen time. Like Store, it's in-memory only: a machine that
+// re-registers after a restart gets a new identity and key.
+type MachineRegistry struct {
+	mu       sync.Mutex
+	machines map[string]Machine // keyed by API key
+	seq      int64
+}
+
+// NewMachineRegistry creates an empty registry.
+func NewMachineRegistry() *MachineRegistry {
+	return &MachineRegistry{machines: make(map[string]Machine)}
+}
+
+// Register issues a new machine identity and API key for name.
+func (r *MachineRegistry) Register(name string) (Machine, error) {
+	key, err := generateAPIKey()
+	if err != nil {
+		return Machine{}, fmt.Errorf("failed to generate api key: %w", err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.seq++
+	now := time.Now()
+	m := Machine{
+		ID:        fmt.Sprintf("machine_%d", r.seq),
+		Name:      name,
+		APIKey:    key,
+		CreatedAt: now,
+		LastSeen:  now,
+	}
+	r.machines[key] = m
+
+	return m, nil
+}
+
+// Authenticate looks up the machine owning apiKey, touching its LastSeen.
+func (r *MachineRegistry) Authenticate(apiKey string) (Machine, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	m, ok := r.machines[apiKey]
+	if !ok {
+		return Machine{}, false
+	}
+
+	m.LastSeen = time.Now()
+	r.machines[apiKey] = m
+	return m, true
+}
+
+// List returns a snapshot of every registered machine, most recently seen
+// first.
+func (r *MachineRegistry) List() []Machine {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	out := make([]Machine, 0, len(r.machines))
+	for _, m := range r.machines {
+		out = append(out, m)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].LastSeen.After(out[j].LastSeen)
+	})
+	return out
+}
+
+// RequireMachineAuth rejects any request without a valid "Bearer <api_key>"
+// Authorization header issued by Register, mirroring
+// middleware.RequireBearerToken but checked against per-machine keys instead
+// of a single shared token.
+func (r *MachineRegistry) RequireMachineAuth(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		const prefix = "Bearer "
+
+		header := req.Header.Get("Authorization")
+		if !strings.HasPrefix(header, prefix) {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		m, ok := r.Authenticate(strings.TrimPrefix(header, prefix))
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		ctx := context.WithValue(req.Context(), machineContextKey{}, m)
+		next.ServeHTTP(w, req.WithContext(ctx))
+	})
+}
+
+func generateAPIKey() (string, error) {
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
@@ -0,0 +1,106 @@
+package decisions
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestMachineRegistryRegisterAndAuthenticate(t *testing.T) {
+	registry := NewMachineRegistry()
+
+	m, err := registry.Register("agent-1")
+	if err != nil {
+		t.Fatalf("Register returned error: %v", err)
+	}
+	if m.APIKey == "" {
+		t.Fatal("expected a non-empty API key")
+	}
+
+	got, ok := registry.Authenticate(m.APIKey)
+	if !ok {
+		t.Fatal("expected Authenticate to succeed with the issued key")
+	}
+	if got.Name != "agent-1" {
+		t.Errorf("expected name agent-1, got %s", got.Name)
+	}
+
+	if _, ok := registry.Authenticate("not-a-real-key"); ok {
+		t.Error("expected Authenticate to fail for an unknown key")
+	}
+}
+
+func TestMachineRegistryListMostRecentlySeenFirst(t *testing.T) {
+	registry := NewMachineRegistry()
+
+	a, _ := registry.Register("a")
+	registry.Register("b")
+
+	registry.Authenticate(a.APIKey)
+
+	if len(registry.List()) != 2 {
+		t.Fatalf("expected 2 registered machines, got %d", len(registry.List()))
+	}
+	if first := registry.List()[0]; first.Name != "a" {
+		t.Errorf("expected most recently seen machine a first, got %s", first.Name)
+	}
+}
+
+func TestRequireMachineAuth(t *testing.T) {
+	registry := NewMachineRegistry()
+	m, _ := registry.Register("agent-1")
+
+	var gotMachine Machine
+	var gotOK bool
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotMachine, gotOK = MachineFromContext(r.Context())
+		w.WriteHeader(http.StatusOK)
+	})
+
+	handler := registry.RequireMachineAuth(next)
+
+	t.Run("valid key", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/decisions", nil)
+		req.Header.Set("Authorization", "Bearer "+m.APIKey)
+		rec := httptest.NewRecorder()
+
+		handler.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected status 200, got %d", rec.Code)
+		}
+		if !gotOK || gotMachine.Name != "agent-1" {
+			t.Errorf("expected the authenticated machine in context, got %+v (ok=%v)", gotMachine, gotOK)
+		}
+	})
+
+	t.Run("missing header", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/decisions", nil)
+		rec := httptest.NewRecorder()
+
+		handler.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusUnauthorized {
+			t.Fatalf("expected status 401, got %d", rec.Code)
+		}
+	})
+
+	t.Run("unknown key", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/decisions", nil)
+		req.Header.Set("Authorization", "Bearer bogus")
+		rec := httptest.NewRecorder()
+
+		handler.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusUnauthorized {
+			t.Fatalf("expected status 401, got %d", rec.Code)
+		}
+	})
+}
+
+func TestMachineFromContextAbsent(t *testing.T) {
+	if _, ok := MachineFromContext(context.Background()); ok {
+		t.Error("expected no machine in an empty context")
+	}
+}
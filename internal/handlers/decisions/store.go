@@ -0,0 +1,177 @@
+// Package decisions implements a CrowdSec-LAPI-style store of active
+// threats ("decisions") that external bouncers (DNS sinkholes, firewalls,
+// browser extensions) can poll or long-poll subscribe to.
+package decisions
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Decision is a single active threat, scoped to a value (e.g. a domain).
+type Decision struct {
+	ID        string    `json:"id"`
+	Scope     string    `json:"scope"` // e.g. "domain"
+	Value     string    `json:"value"`
+	Type      string    `json:"type"` // e.g. "ban"
+	Scenario  string    `json:"scenario"`
+	Origin    string    `json:"origin"`
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// Event is a single entry in the store's change log: either a Decision
+// being added, or one expiring (Deleted).
+type Event struct {
+	Seq      int64    `json:"seq"`
+	Decision Decision `json:"decision"`
+	Deleted  bool     `json:"deleted"`
+}
+
+// maxLogEvents bounds how many Events Store.log retains. Add and ExpireDue
+// both trim the oldest entries past this, so a store fed by a live
+// certstream feed doesn't grow its log forever. A subscriber whose "since"
+// cursor falls further behind than maxLogEvents worth of events misses the
+// gap; GET /decisions/stream's response already reports the store's latest
+// seq, so a client that notices a gap can tell and re-sync via List.
+const maxLogEvents = 10000
+
+// trimSlack delays trimLogLocked's reallocation until the log is this much
+// over maxLogEvents, then trims back down to maxLogEvents. Without this, a
+// sustained feed sitting right at the cap would reallocate and copy the
+// whole log on every single Add/ExpireDue call once at capacity.
+const trimSlack = maxLogEvents / 10
+
+// Store holds the currently active decisions plus a change log capped at
+// maxLogEvents+trimSlack entries, so that GET /decisions/stream can resume
+// from a "since" cursor.
+type Store struct {
+	mu        sync.Mutex
+	decisions map[string]Decision
+	log       []Event
+	seq       int64
+	notify    chan struct{}
+}
+
+// NewStore creates an empty decision store.
+func NewStore() *Store {
+	return &Store{
+		decisions: make(map[string]Decision),
+		notify:    make(chan struct{}),
+	}
+}
+
+// Add stores a new decision, assigning it an ID if one isn't set, and wakes
+// up anyone long-polling the stream endpoint.
+func (s *Store) Add(d Decision) Decision {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.seq++
+	if d.ID == "" {
+		d.ID = fmt.Sprintf("decision_%d", s.seq)
+	}
+
+	s.decisions[d.ID] = d
+	s.log = append(s.log, Event{Seq: s.seq, Decision: d})
+	s.trimLogLocked()
+	s.broadcastLocked()
+
+	return d
+}
+
+// ExpireDue removes every decision whose ExpiresAt is at or before now,
+// recording a Deleted event for each so stream subscribers learn about it.
+func (s *Store) ExpireDue(now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	changed := false
+	for id, d := range s.decisions {
+		if !now.Before(d.ExpiresAt) {
+			delete(s.decisions, id)
+			s.seq++
+			s.log = append(s.log, Event{Seq: s.seq, Decision: d, Deleted: true})
+			changed = true
+		}
+	}
+
+	if changed {
+		s.trimLogLocked()
+		s.broadcastLocked()
+	}
+}
+
+// trimLogLocked drops the oldest log entries once there are more than
+// maxLogEvents+trimSlack of them, bringing the log back down to
+// maxLogEvents. Callers must hold s.mu.
+func (s *Store) trimLogLocked() {
+	if len(s.log) <= maxLogEvents+trimSlack {
+		return
+	}
+
+	trimmed := make([]Event, maxLogEvents)
+	copy(trimmed, s.log[len(s.log)-maxLogEvents:])
+	s.log = trimmed
+}
+
+// Run periodically expires due decisions until ctx is done. Callers
+// typically start this once as a background goroutine.
+func (s *Store) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			s.ExpireDue(now)
+		}
+	}
+}
+
+// List returns the currently active decisions, optionally filtered by scope
+// and/or value.
+func (s *Store) List(scope, value string) []Decision {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	out := make([]Decision, 0, len(s.decisions))
+	for _, d := range s.decisions {
+		if scope != "" && d.Scope != scope {
+			continue
+		}
+		if value != "" && d.Value != value {
+			continue
+		}
+		out = append(out, d)
+	}
+
+	return out
+}
+
+// Since returns every log event after the given sequence number, the
+// store's latest sequence number, and a channel that's closed the next time
+// the store changes (for long-polling).
+func (s *Store) Since(since int64) (events []Event, latest int64, wait <-chan struct{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, e := range s.log {
+		if e.Seq > since {
+			events = append(events, e)
+		}
+	}
+
+	return events, s.seq, s.notify
+}
+
+// broadcastLocked wakes up any goroutine waiting on the current notify
+// channel. Callers must hold s.mu.
+func (s *Store) broadcastLocked() {
+	close(s.notify)
+	s.notify = make(chan struct{})
+}
@@ -0,0 +1,136 @@
+package decisions
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStoreAddAndList(t *testing.T) {
+	store := NewStore()
+
+	store.Add(Decision{Scope: "domain", Value: "paypa1.com", Type: "ban", Scenario: "typosquat"})
+	store.Add(Decision{Scope: "domain", Value: "amaz0n.net", Type: "ban", Scenario: "typosquat"})
+
+	all := store.List("", "")
+	if len(all) != 2 {
+		t.Fatalf("expected 2 decisions, got %d", len(all))
+	}
+
+	filtered := store.List("domain", "paypa1.com")
+	if len(filtered) != 1 {
+		t.Fatalf("expected 1 decision, got %d", len(filtered))
+	}
+	if filtered[0].Value != "paypa1.com" {
+		t.Errorf("expected paypa1.com, got %s", filtered[0].Value)
+	}
+}
+
+func TestStoreExpireDue(t *testing.T) {
+	store := NewStore()
+
+	now := time.Now()
+	store.Add(Decision{Scope: "domain", Value: "expired.com", ExpiresAt: now.Add(-time.Minute)})
+	store.Add(Decision{Scope: "domain", Value: "active.com", ExpiresAt: now.Add(time.Hour)})
+
+	store.ExpireDue(now)
+
+	active := store.List("", "")
+	if len(active) != 1 {
+		t.Fatalf("expected 1 active decision after expiry, got %d", len(active))
+	}
+	if active[0].Value != "active.com" {
+		t.Errorf("expected active.com to remain, got %s", active[0].Value)
+	}
+}
+
+func TestStoreSince(t *testing.T) {
+	store := NewStore()
+
+	store.Add(Decision{Scope: "domain", Value: "first.com"})
+	events, latest, _ := store.Since(0)
+	if len(events) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(events))
+	}
+	if latest != 1 {
+		t.Fatalf("expected latest seq 1, got %d", latest)
+	}
+
+	store.Add(Decision{Scope: "domain", Value: "second.com"})
+	events, latest, _ = store.Since(1)
+	if len(events) != 1 {
+		t.Fatalf("expected 1 new event since seq 1, got %d", len(events))
+	}
+	if events[0].Decision.Value != "second.com" {
+		t.Errorf("expected second.com, got %s", events[0].Decision.Value)
+	}
+	if latest != 2 {
+		t.Fatalf("expected latest seq 2, got %d", latest)
+	}
+}
+
+func TestStoreLogIsBounded(t *testing.T) {
+	store := NewStore()
+
+	// Trim back to maxLogEvents happens exactly once here, right as the log
+	// crosses maxLogEvents+trimSlack; everything added after that rides
+	// under the slack again until the log grows back up to the threshold.
+	total := maxLogEvents + trimSlack + 1
+	for i := 0; i < total; i++ {
+		store.Add(Decision{Scope: "domain", Value: "flood.com"})
+	}
+
+	events, latest, _ := store.Since(0)
+	if len(events) != maxLogEvents {
+		t.Fatalf("expected log trimmed back to %d events, got %d", maxLogEvents, len(events))
+	}
+	if latest != int64(total) {
+		t.Fatalf("expected latest seq %d, got %d", total, latest)
+	}
+	wantOldest := int64(total - maxLogEvents + 1)
+	if events[0].Seq != wantOldest {
+		t.Errorf("expected oldest retained event to be seq %d, got %d", wantOldest, events[0].Seq)
+	}
+
+	// However many more events arrive, the log never grows past
+	// maxLogEvents+trimSlack.
+	for i := 0; i < maxLogEvents; i++ {
+		store.Add(Decision{Scope: "domain", Value: "flood.com"})
+	}
+	events, _, _ = store.Since(0)
+	if len(events) > maxLogEvents+trimSlack {
+		t.Fatalf("expected log capped at %d events, got %d", maxLogEvents+trimSlack, len(events))
+	}
+}
+
+func TestStoreLogStaysWithinSlackBeforeTrimming(t *testing.T) {
+	store := NewStore()
+
+	for i := 0; i < maxLogEvents+trimSlack; i++ {
+		store.Add(Decision{Scope: "domain", Value: "flood.com"})
+	}
+
+	events, _, _ := store.Since(0)
+	if len(events) != maxLogEvents+trimSlack {
+		t.Fatalf("expected log not yet trimmed at %d events, got %d", maxLogEvents+trimSlack, len(events))
+	}
+}
+
+func TestStoreSinceWaitWakesOnAdd(t *testing.T) {
+	store := NewStore()
+
+	_, _, wait := store.Since(0)
+
+	done := make(chan struct{})
+	go func() {
+		<-wait
+		close(done)
+	}()
+
+	store.Add(Decision{Scope: "domain", Value: "woken.com"})
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("expected wait channel to close after Add")
+	}
+}
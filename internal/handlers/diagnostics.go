@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"net/http"
+)
+
+// Diagnostician produces the same redacted support bundle as the CLI's
+// "openbpl diagnostics" subcommand. pkg/core.Engine implements it; cmd/server
+// supplies its own implementation since it has no Engine of its own.
+type Diagnostician interface {
+	Diagnostics() ([]byte, error)
+}
+
+// DiagnosticsHandlers exposes a Diagnostician over HTTP, for hosts where
+// the CLI can't be run directly.
+type DiagnosticsHandlers struct {
+	Provider Diagnostician
+}
+
+// NewDiagnosticsHandlers creates handlers backed by provider.
+func NewDiagnosticsHandlers(provider Diagnostician) *DiagnosticsHandlers {
+	return &DiagnosticsHandlers{Provider: provider}
+}
+
+// Get handles GET /api/v1/diagnostics, streaming a zip-format support
+// bundle. Mount it behind an auth-gating middleware - the bundle includes
+// config and storage/runtime details that shouldn't be publicly readable.
+func (h *DiagnosticsHandlers) Get(w http.ResponseWriter, r *http.Request) {
+	bundle, err := h.Provider.Diagnostics()
+	if err != nil {
+		writeErrorResponse(w, http.StatusInternalServerError, "Failed to build diagnostics bundle: "+err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", `attachment; filename="diagnostics.zip"`)
+	w.WriteHeader(http.StatusOK)
+	w.Write(bundle)
+}
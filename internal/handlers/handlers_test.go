@@ -2,10 +2,16 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"openbpl/internal/middleware"
 )
 
 func TestHealth(t *testing.T) {
@@ -93,6 +99,46 @@ func TestStatus(t *testing.T) {
 	}
 }
 
+func TestHealthIncrementsRequestMetrics(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	metrics := middleware.NewHTTPMetrics(reg)
+	handler := metrics.Middleware(http.HandlerFunc(Health))
+
+	req := httptest.NewRequest("GET", "/health", nil)
+	req.Pattern = "GET /health"
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, req)
+
+	count, err := testutil.GatherAndCount(reg, "openbpl_http_requests_total")
+	if err != nil {
+		t.Fatalf("GatherAndCount failed: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("Expected 1 sample for openbpl_http_requests_total, got %d", count)
+	}
+}
+
+func TestStatusIncrementsRequestMetrics(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	metrics := middleware.NewHTTPMetrics(reg)
+	handler := metrics.Middleware(http.HandlerFunc(Status))
+
+	req := httptest.NewRequest("GET", "/api/v1/status", nil)
+	req.Pattern = "GET /api/v1/status"
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, req)
+
+	count, err := testutil.GatherAndCount(reg, "openbpl_http_requests_total")
+	if err != nil {
+		t.Fatalf("GatherAndCount failed: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("Expected 1 sample for openbpl_http_requests_total, got %d", count)
+	}
+}
+
 func TestHome(t *testing.T) {
 	req := httptest.NewRequest("GET", "/", nil)
 	w := httptest.NewRecorder()
@@ -246,3 +292,53 @@ func TestWriteErrorResponse(t *testing.T) {
 		t.Errorf("Expected error 'test error', got '%s'", response.Error)
 	}
 }
+
+type stubDiagnostician struct {
+	bundle []byte
+	err    error
+}
+
+func (s *stubDiagnostician) Diagnostics() ([]byte, error) {
+	return s.bundle, s.err
+}
+
+func TestDiagnosticsHandlersGet(t *testing.T) {
+	want := []byte("fake zip contents")
+	h := NewDiagnosticsHandlers(&stubDiagnostician{bundle: want})
+
+	req := httptest.NewRequest("GET", "/api/v1/diagnostics", nil)
+	w := httptest.NewRecorder()
+
+	h.Get(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", w.Code)
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "application/zip" {
+		t.Errorf("Expected Content-Type application/zip, got %q", ct)
+	}
+	if w.Body.String() != string(want) {
+		t.Errorf("Expected body %q, got %q", want, w.Body.String())
+	}
+}
+
+func TestDiagnosticsHandlersGetError(t *testing.T) {
+	h := NewDiagnosticsHandlers(&stubDiagnostician{err: errors.New("bundle failed")})
+
+	req := httptest.NewRequest("GET", "/api/v1/diagnostics", nil)
+	w := httptest.NewRecorder()
+
+	h.Get(w, req)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("Expected status 500, got %d", w.Code)
+	}
+
+	var response Response
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if response.Status != "error" {
+		t.Errorf("Expected status 'error', got '%s'", response.Status)
+	}
+}
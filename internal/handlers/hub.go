@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"openbpl/pkg/hub"
+)
+
+// HubHandlers exposes management endpoints for a hub.Hub.
+type HubHandlers struct {
+	Hub *hub.Hub
+}
+
+// NewHubHandlers creates handlers backed by h.
+func NewHubHandlers(h *hub.Hub) *HubHandlers {
+	return &HubHandlers{Hub: h}
+}
+
+// Items handles GET /api/v1/hub/items, listing every item currently
+// installed in the hub cache.
+func (h *HubHandlers) Items(w http.ResponseWriter, r *http.Request) {
+	response := Response{
+		Status: "ok",
+		Data: map[string]interface{}{
+			"items": h.Hub.List(),
+		},
+	}
+	writeJSONResponse(w, http.StatusOK, response)
+}
+
+type installRequest struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// Install handles POST /api/v1/hub/install, installing the requested item -
+// or upgrading it, since Install always re-verifies and re-caches the
+// content. An empty Version installs the latest version in the index.
+func (h *HubHandlers) Install(w http.ResponseWriter, r *http.Request) {
+	var req installRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid request body: "+err.Error())
+		return
+	}
+	if req.Name == "" {
+		writeErrorResponse(w, http.StatusBadRequest, "name is required")
+		return
+	}
+
+	if err := h.Hub.Install(req.Name, req.Version); err != nil {
+		writeErrorResponse(w, http.StatusInternalServerError, "Failed to install hub item: "+err.Error())
+		return
+	}
+
+	response := Response{
+		Status:  "ok",
+		Message: "Installed " + req.Name,
+	}
+	writeJSONResponse(w, http.StatusOK, response)
+}
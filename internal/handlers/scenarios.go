@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"net/http"
+
+	"openbpl/pkg/core/scenarios"
+)
+
+// ScenarioHandlers exposes management endpoints for a scenarios.Engine.
+type ScenarioHandlers struct {
+	Engine *scenarios.Engine
+}
+
+// NewScenarioHandlers creates handlers backed by engine.
+func NewScenarioHandlers(engine *scenarios.Engine) *ScenarioHandlers {
+	return &ScenarioHandlers{Engine: engine}
+}
+
+// Reload handles POST /api/v1/scenarios/reload, re-reading the scenario
+// directory from disk without restarting the server.
+func (h *ScenarioHandlers) Reload(w http.ResponseWriter, r *http.Request) {
+	if err := h.Engine.Reload(); err != nil {
+		writeErrorResponse(w, http.StatusInternalServerError, "Failed to reload scenarios: "+err.Error())
+		return
+	}
+
+	response := Response{
+		Status:  "ok",
+		Message: "Scenarios reloaded",
+		Data: map[string]interface{}{
+			"count": len(h.Engine.Scenarios()),
+		},
+	}
+
+	writeJSONResponse(w, http.StatusOK, response)
+}
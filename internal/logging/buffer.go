@@ -0,0 +1,61 @@
+package logging
+
+import (
+	"io"
+	"log"
+	"strings"
+	"sync"
+)
+
+// recentCapacity caps how many recent log lines CaptureRecent retains for
+// diagnostics bundles; older lines are discarded as new ones arrive.
+const recentCapacity = 200
+
+var recent = &ringBuffer{}
+
+type ringBuffer struct {
+	mu    sync.Mutex
+	lines []string
+}
+
+func (b *ringBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, line := range strings.Split(strings.TrimRight(string(p), "\n"), "\n") {
+		if line == "" {
+			continue
+		}
+		b.lines = append(b.lines, line)
+	}
+	if len(b.lines) > recentCapacity {
+		b.lines = b.lines[len(b.lines)-recentCapacity:]
+	}
+	return len(p), nil
+}
+
+func (b *ringBuffer) lastN(n int) []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if n <= 0 || n > len(b.lines) {
+		n = len(b.lines)
+	}
+	out := make([]string, n)
+	copy(out, b.lines[len(b.lines)-n:])
+	return out
+}
+
+// CaptureRecent tees the standard logger's output - used by the log.Printf
+// calls throughout the ingestion pipeline - into an in-memory ring buffer
+// on top of existing (e.g. os.Stderr), so RecentLines can report them in a
+// diagnostics bundle. Call once at process startup.
+func CaptureRecent(existing io.Writer) {
+	log.SetOutput(io.MultiWriter(existing, recent))
+}
+
+// RecentLines returns up to n of the most recent captured lines, oldest
+// first.
+func RecentLines(n int) []string {
+	return recent.lastN(n)
+}
@@ -0,0 +1,31 @@
+package logging
+
+import "testing"
+
+func TestRingBufferLastN(t *testing.T) {
+	b := &ringBuffer{}
+	b.Write([]byte("line one\nline two\n"))
+	b.Write([]byte("line three\n"))
+
+	got := b.lastN(2)
+	want := []string{"line two", "line three"}
+	if len(got) != len(want) {
+		t.Fatalf("Expected %d lines, got %d: %v", len(want), len(got), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Expected line %d to be %q, got %q", i, want[i], got[i])
+		}
+	}
+}
+
+func TestRingBufferDropsOldestPastCapacity(t *testing.T) {
+	b := &ringBuffer{}
+	for i := 0; i < recentCapacity+10; i++ {
+		b.Write([]byte("line\n"))
+	}
+
+	if len(b.lines) != recentCapacity {
+		t.Errorf("Expected buffer capped at %d lines, got %d", recentCapacity, len(b.lines))
+	}
+}
@@ -0,0 +1,87 @@
+// Package logging configures the structured logger used in place of the
+// package-level log.Printf calls throughout the ingestion pipeline.
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// New returns a slog.Logger for the given level ("debug", "info", "warn",
+// "error"; unrecognized or empty defaults to info) and format ("json" for
+// log aggregators, anything else for human-readable text).
+func New(level, format string) *slog.Logger {
+	opts := &slog.HandlerOptions{Level: parseLevel(level)}
+
+	var handler slog.Handler
+	if format == "json" {
+		handler = slog.NewJSONHandler(os.Stdout, opts)
+	} else {
+		handler = slog.NewTextHandler(os.Stdout, opts)
+	}
+
+	return slog.New(handler)
+}
+
+func parseLevel(level string) slog.Level {
+	switch strings.ToLower(level) {
+	case "debug":
+		return slog.LevelDebug
+	case "warn", "warning":
+		return slog.LevelWarn
+	case "error":
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
+
+// correlationKey identifies a value With attaches to a logger as a field
+// named after itself - e.g. eventIDKey adds "event_id" - so a single
+// certificate's journey through source -> enricher -> detector -> enforcer,
+// or a single HTTP request, can be grepped by one ID.
+type correlationKey string
+
+const (
+	eventIDKey   correlationKey = "event_id"
+	requestIDKey correlationKey = "request_id"
+)
+
+// WithEventID returns ctx with id attached for With to pick up.
+func WithEventID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, eventIDKey, id)
+}
+
+// WithRequestID returns ctx with id attached for With to pick up.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey, id)
+}
+
+// FromContextRequestID returns the request ID attached to ctx via
+// WithRequestID, if any.
+func FromContextRequestID(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(requestIDKey).(string)
+	return id, ok
+}
+
+// FromContextEventID returns the event ID attached to ctx via WithEventID,
+// if any.
+func FromContextEventID(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(eventIDKey).(string)
+	return id, ok
+}
+
+// With returns logger tagged with whatever correlation IDs ctx carries
+// (event_id, request_id), so every log line logged through it can be
+// grepped back to the request or pipeline run that produced it.
+func With(ctx context.Context, logger *slog.Logger) *slog.Logger {
+	if id, ok := ctx.Value(eventIDKey).(string); ok && id != "" {
+		logger = logger.With(string(eventIDKey), id)
+	}
+	if id, ok := ctx.Value(requestIDKey).(string); ok && id != "" {
+		logger = logger.With(string(requestIDKey), id)
+	}
+	return logger
+}
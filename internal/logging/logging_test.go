@@ -0,0 +1,66 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestNewUsesJSONFormat(t *testing.T) {
+	logger := New("info", "json")
+	if !logger.Handler().Enabled(context.Background(), slog.LevelInfo) {
+		t.Fatal("expected handler to be enabled for info-level logs")
+	}
+}
+
+func TestNewUsesTextFormatByDefault(t *testing.T) {
+	logger := New("info", "text")
+	if !logger.Handler().Enabled(context.Background(), slog.LevelInfo) {
+		t.Fatal("expected handler to be enabled for info-level logs")
+	}
+}
+
+func TestNewParsesLevel(t *testing.T) {
+	logger := New("warn", "text")
+	if logger.Handler().Enabled(context.Background(), slog.LevelInfo) {
+		t.Error("expected info-level logs to be disabled at warn level")
+	}
+	if !logger.Handler().Enabled(context.Background(), slog.LevelWarn) {
+		t.Error("expected warn-level logs to be enabled at warn level")
+	}
+}
+
+func TestWithAttachesCorrelationIDs(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+
+	ctx := WithEventID(context.Background(), "evt-1")
+	ctx = WithRequestID(ctx, "req-1")
+
+	With(ctx, logger).Info("something happened")
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("failed to decode log line: %v", err)
+	}
+	if entry["event_id"] != "evt-1" {
+		t.Errorf("expected event_id %q, got %v", "evt-1", entry["event_id"])
+	}
+	if entry["request_id"] != "req-1" {
+		t.Errorf("expected request_id %q, got %v", "req-1", entry["request_id"])
+	}
+}
+
+func TestWithOmitsUnsetCorrelationIDs(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+
+	With(context.Background(), logger).Info("something happened")
+
+	if strings.Contains(buf.String(), "event_id") || strings.Contains(buf.String(), "request_id") {
+		t.Errorf("expected no correlation ID fields, got %q", buf.String())
+	}
+}
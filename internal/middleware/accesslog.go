@@ -0,0 +1,156 @@
+package middleware
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"gopkg.in/natefinch/lumberjack.v2"
+
+	"openbpl/internal/config"
+)
+
+// apacheTimeFormat is the [date] field of the NCSA/Apache Combined Log
+// Format, e.g. "10/Oct/2026:13:55:36 -0700".
+const apacheTimeFormat = "02/Jan/2006:15:04:05 -0700"
+
+// AccessLog returns middleware that writes one line per request to cfg's
+// configured output (stdout, stderr, or a lumberjack-rotated file), in
+// cfg.Format ("text", "combined" - NCSA/Apache Combined Log Format - or
+// "json"). It's the per-request HTTP log, distinct from application-level
+// logging; Logger should be left out of the chain when AccessLog is enabled,
+// since both would otherwise write a line per request.
+func AccessLog(cfg config.AccessLogConfig) Middleware {
+	return accessLogTo(cfg, newAccessLogWriter(cfg))
+}
+
+// accessLogTo is AccessLog with the output writer passed in directly,
+// rather than derived from cfg.Output, so tests can assert against an
+// in-memory buffer instead of stdout/stderr/a real file.
+func accessLogTo(cfg config.AccessLogConfig, out io.Writer) Middleware {
+	writeEntry := accessLogWriterFor(cfg.Format)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			wrapped := &responseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+
+			next.ServeHTTP(wrapped, r)
+
+			writeEntry(out, r, wrapped, start)
+		})
+	}
+}
+
+// newAccessLogWriter opens cfg's configured output.
+func newAccessLogWriter(cfg config.AccessLogConfig) io.Writer {
+	switch cfg.Output {
+	case "stderr":
+		return os.Stderr
+	case "file":
+		return &lumberjack.Logger{
+			Filename:   cfg.Path,
+			MaxSize:    cfg.MaxSizeMB,
+			MaxAge:     cfg.MaxAgeDays,
+			MaxBackups: cfg.MaxBackups,
+		}
+	default:
+		return os.Stdout
+	}
+}
+
+// accessLogEntryWriter writes one log line for a completed request.
+type accessLogEntryWriter func(w io.Writer, r *http.Request, rw *responseWriter, start time.Time)
+
+func accessLogWriterFor(format string) accessLogEntryWriter {
+	switch format {
+	case "json":
+		return writeAccessLogJSON
+	case "text":
+		return writeAccessLogText
+	default:
+		return writeAccessLogCombined
+	}
+}
+
+// writeAccessLogCombined writes a NCSA/Apache Combined Log Format line:
+// host ident authuser [date] "request" status bytes "referer" "user-agent".
+// ident is always "-" (we don't run identd); authuser is the subject Auth
+// authenticated the request as, or "-" if none.
+func writeAccessLogCombined(w io.Writer, r *http.Request, rw *responseWriter, start time.Time) {
+	authuser := "-"
+	if subject, ok := SubjectFromContext(r.Context()); ok {
+		authuser = subject
+	}
+
+	bytes := "-"
+	if rw.bytesWritten > 0 {
+		bytes = strconv.FormatInt(rw.bytesWritten, 10)
+	}
+
+	referer := r.Referer()
+	if referer == "" {
+		referer = "-"
+	}
+	ua := r.UserAgent()
+	if ua == "" {
+		ua = "-"
+	}
+
+	fmt.Fprintf(w, "%s - %s [%s] \"%s %s %s\" %d %s \"%s\" \"%s\"\n",
+		remoteIPKey(r), authuser, start.Format(apacheTimeFormat),
+		r.Method, r.URL.RequestURI(), r.Proto,
+		rw.statusCode, bytes, referer, ua,
+	)
+}
+
+// writeAccessLogText writes a short, human-oriented line similar in spirit
+// to Logger's, but including response size and a timestamp suitable for a
+// log file rather than a terminal.
+func writeAccessLogText(w io.Writer, r *http.Request, rw *responseWriter, start time.Time) {
+	fmt.Fprintf(w, "%s %s %s %d %dB %v %s\n",
+		start.Format(time.RFC3339), r.Method, r.URL.Path,
+		rw.statusCode, rw.bytesWritten, time.Since(start), r.RemoteAddr,
+	)
+}
+
+// accessLogJSONEntry is the JSON shape writeAccessLogJSON emits, one object
+// per line.
+type accessLogJSONEntry struct {
+	Time      string `json:"time"`
+	Method    string `json:"method"`
+	Path      string `json:"path"`
+	Status    int    `json:"status"`
+	Bytes     int64  `json:"bytes"`
+	RemoteIP  string `json:"remote_ip"`
+	AuthUser  string `json:"auth_user,omitempty"`
+	Referer   string `json:"referer,omitempty"`
+	UserAgent string `json:"user_agent,omitempty"`
+	Latency   string `json:"latency"`
+}
+
+func writeAccessLogJSON(w io.Writer, r *http.Request, rw *responseWriter, start time.Time) {
+	entry := accessLogJSONEntry{
+		Time:      start.Format(time.RFC3339),
+		Method:    r.Method,
+		Path:      r.URL.Path,
+		Status:    rw.statusCode,
+		Bytes:     rw.bytesWritten,
+		RemoteIP:  remoteIPKey(r),
+		Referer:   r.Referer(),
+		UserAgent: r.UserAgent(),
+		Latency:   time.Since(start).String(),
+	}
+	if subject, ok := SubjectFromContext(r.Context()); ok {
+		entry.AuthUser = subject
+	}
+
+	if err := json.NewEncoder(w).Encode(entry); err != nil {
+		log.Printf("⚠️ Failed to write access log entry: %v", err)
+	}
+}
@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"openbpl/internal/config"
+)
+
+func TestAccessLogCombined(t *testing.T) {
+	var buf bytes.Buffer
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("hello"))
+	})
+	handler := accessLogTo(config.AccessLogConfig{Format: "combined"}, &buf)(next)
+
+	req := httptest.NewRequest(http.MethodGet, "/widgets?x=1", nil)
+	req.RemoteAddr = "203.0.113.9:1234"
+	req.Header.Set("Referer", "https://example.com")
+	req.Header.Set("User-Agent", "test-agent")
+	req = req.WithContext(context.WithValue(req.Context(), subjectContextKey{}, "alice"))
+
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	line := buf.String()
+	for _, want := range []string{"203.0.113.9", "- alice [", `"GET /widgets?x=1 HTTP/1.1"`, " 200 5 ", `"https://example.com"`, `"test-agent"`} {
+		if !strings.Contains(line, want) {
+			t.Errorf("expected combined log line to contain %q, got: %s", want, line)
+		}
+	}
+}
+
+func TestAccessLogJSON(t *testing.T) {
+	var buf bytes.Buffer
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("hello"))
+	})
+	handler := accessLogTo(config.AccessLogConfig{Format: "json"}, &buf)(next)
+
+	req := httptest.NewRequest(http.MethodGet, "/widgets", nil)
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	var entry accessLogJSONEntry
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("expected valid JSON, got error: %v (line: %s)", err, buf.String())
+	}
+	if entry.Method != "GET" || entry.Path != "/widgets" || entry.Status != http.StatusOK || entry.Bytes != 5 {
+		t.Errorf("unexpected entry: %+v", entry)
+	}
+}
+
+func TestAccessLogText(t *testing.T) {
+	var buf bytes.Buffer
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("hello"))
+	})
+	handler := accessLogTo(config.AccessLogConfig{Format: "text"}, &buf)(next)
+
+	req := httptest.NewRequest(http.MethodGet, "/widgets", nil)
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	line := buf.String()
+	if !strings.Contains(line, "GET") || !strings.Contains(line, "/widgets") || !strings.Contains(line, "200") {
+		t.Errorf("expected text log line to contain method/path/status, got: %s", line)
+	}
+}
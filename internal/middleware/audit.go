@@ -0,0 +1,129 @@
+package middleware
+
+import (
+	"database/sql"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// AuditEntry is a single recorded mutating-request event.
+type AuditEntry struct {
+	Who       string        `json:"who"`
+	Method    string        `json:"method"`
+	Path      string        `json:"path"`
+	Status    int           `json:"status"`
+	Latency   time.Duration `json:"latency"`
+	RequestID string        `json:"request_id"`
+	Timestamp time.Time     `json:"timestamp"`
+}
+
+// AuditSink persists AuditEntry records. Implementations must be safe for
+// concurrent use, since Audit calls SaveAudit from every request's own
+// goroutine.
+type AuditSink interface {
+	SaveAudit(entry AuditEntry) error
+}
+
+// MemoryAuditSink is an in-memory AuditSink, useful for tests and for
+// deployments that only need a rolling audit trail rather than a durable
+// one backed by Storage.
+type MemoryAuditSink struct {
+	mu      sync.Mutex
+	entries []AuditEntry
+}
+
+// NewMemoryAuditSink creates an empty sink.
+func NewMemoryAuditSink() *MemoryAuditSink {
+	return &MemoryAuditSink{}
+}
+
+// SaveAudit appends entry to the in-memory trail.
+func (s *MemoryAuditSink) SaveAudit(entry AuditEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries = append(s.entries, entry)
+	return nil
+}
+
+// Entries returns a snapshot of every audit entry recorded so far.
+func (s *MemoryAuditSink) Entries() []AuditEntry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	out := make([]AuditEntry, len(s.entries))
+	copy(out, s.entries)
+	return out
+}
+
+// DBAuditSink persists AuditEntry records into db's audit_log table (see
+// internal/database/migrations), giving Audit a trail that survives a
+// restart instead of MemoryAuditSink's in-process one. It's the sink
+// production deployments should wire up once a database is configured.
+type DBAuditSink struct {
+	db *sql.DB
+}
+
+// NewDBAuditSink returns a sink that inserts into db's audit_log table.
+func NewDBAuditSink(db *sql.DB) *DBAuditSink {
+	return &DBAuditSink{db: db}
+}
+
+// SaveAudit inserts entry into audit_log.
+func (s *DBAuditSink) SaveAudit(entry AuditEntry) error {
+	_, err := s.db.Exec(
+		`INSERT INTO audit_log (who, method, path, status, latency_ms, request_id, occurred_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		entry.Who, entry.Method, entry.Path, entry.Status,
+		entry.Latency.Milliseconds(), entry.RequestID, entry.Timestamp,
+	)
+	return err
+}
+
+// mutatingMethods is the set of HTTP methods Audit records; GET/HEAD/OPTIONS
+// requests are read-only and not logged.
+var mutatingMethods = map[string]bool{
+	http.MethodPost:   true,
+	http.MethodPut:    true,
+	http.MethodPatch:  true,
+	http.MethodDelete: true,
+}
+
+// Audit returns middleware that records {who, method, path, status,
+// latency, requestID} into sink for every mutating (POST/PUT/PATCH/DELETE)
+// request. "who" is the subject Auth authenticated the request as - Audit
+// should sit after Auth in the chain - or "anonymous" if no subject is in
+// context.
+func Audit(sink AuditSink) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !mutatingMethods[r.Method] {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			start := time.Now()
+			wrapped := &responseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+			next.ServeHTTP(wrapped, r)
+
+			who := "anonymous"
+			if subject, ok := SubjectFromContext(r.Context()); ok {
+				who = subject
+			}
+
+			entry := AuditEntry{
+				Who:       who,
+				Method:    r.Method,
+				Path:      r.URL.Path,
+				Status:    wrapped.statusCode,
+				Latency:   time.Since(start),
+				RequestID: r.Header.Get("X-Request-Id"),
+				Timestamp: start,
+			}
+			if err := sink.SaveAudit(entry); err != nil {
+				log.Printf("⚠️ Failed to record audit entry: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestStaticTokenStoreAuthenticate(t *testing.T) {
+	store := StaticTokenStore{"alice": "key-a", "bob": "key-b"}
+
+	subject, ok := store.Authenticate("key-a")
+	if !ok || subject != "alice" {
+		t.Errorf("expected alice, got %s (ok=%v)", subject, ok)
+	}
+
+	if _, ok := store.Authenticate("bogus"); ok {
+		t.Error("expected Authenticate to fail for an unknown key")
+	}
+}
+
+func newAuthedRequest(apiKey string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/decisions", nil)
+	req.Header.Set("Authorization", "Bearer "+apiKey)
+	req.Header.Set("X-Request-Id", "req-1")
+	req.Header.Set("Date", time.Now().UTC().Format(http.TimeFormat))
+	return req
+}
+
+func TestAuth(t *testing.T) {
+	store := StaticTokenStore{"alice": "key-a"}
+
+	var gotSubject string
+	var gotOK bool
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotSubject, gotOK = SubjectFromContext(r.Context())
+		w.WriteHeader(http.StatusOK)
+	})
+	handler := Auth(store)(next)
+
+	t.Run("valid bearer key", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, newAuthedRequest("key-a"))
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected status 200, got %d", rec.Code)
+		}
+		if !gotOK || gotSubject != "alice" {
+			t.Errorf("expected subject alice in context, got %s (ok=%v)", gotSubject, gotOK)
+		}
+	})
+
+	t.Run("unknown key", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, newAuthedRequest("bogus"))
+
+		if rec.Code != http.StatusUnauthorized {
+			t.Fatalf("expected status 401, got %d", rec.Code)
+		}
+	})
+
+	t.Run("missing X-Request-Id", func(t *testing.T) {
+		req := newAuthedRequest("key-a")
+		req.Header.Del("X-Request-Id")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusUnauthorized {
+			t.Fatalf("expected status 401, got %d", rec.Code)
+		}
+	})
+
+	t.Run("clock-skewed Date header", func(t *testing.T) {
+		req := newAuthedRequest("key-a")
+		req.Header.Set("Date", time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat))
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusUnauthorized {
+			t.Fatalf("expected status 401, got %d", rec.Code)
+		}
+	})
+}
+
+func TestSubjectFromContextAbsent(t *testing.T) {
+	if _, ok := SubjectFromContext(httptest.NewRequest(http.MethodGet, "/", nil).Context()); ok {
+		t.Error("expected no subject in an empty context")
+	}
+}
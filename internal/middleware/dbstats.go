@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"database/sql"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// DBStatsCollector exports database/sql.DBStats as Prometheus gauges:
+// open connections, in-use connections, and the cumulative wait count.
+// Unlike HTTPMetrics, whose counters update on every request, these are
+// computed on each scrape via Collect, since DBStats is already the
+// point-in-time snapshot sql.DB keeps for us.
+type DBStatsCollector struct {
+	db        *sql.DB
+	openConns *prometheus.Desc
+	inUse     *prometheus.Desc
+	waitCount *prometheus.Desc
+}
+
+// NewDBStatsCollector returns a collector for db's connection pool stats.
+// Register it against reg (e.g. via RegisterDBStats) once a *sql.DB exists.
+func NewDBStatsCollector(db *sql.DB) *DBStatsCollector {
+	return &DBStatsCollector{
+		db: db,
+		openConns: prometheus.NewDesc(
+			"openbpl_db_open_connections",
+			"Number of established connections to the database, both in use and idle.",
+			nil, nil,
+		),
+		inUse: prometheus.NewDesc(
+			"openbpl_db_in_use_connections",
+			"Number of connections currently in use.",
+			nil, nil,
+		),
+		waitCount: prometheus.NewDesc(
+			"openbpl_db_wait_count_total",
+			"Total number of connections a caller has had to wait for.",
+			nil, nil,
+		),
+	}
+}
+
+func (c *DBStatsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.openConns
+	ch <- c.inUse
+	ch <- c.waitCount
+}
+
+func (c *DBStatsCollector) Collect(ch chan<- prometheus.Metric) {
+	stats := c.db.Stats()
+	ch <- prometheus.MustNewConstMetric(c.openConns, prometheus.GaugeValue, float64(stats.OpenConnections))
+	ch <- prometheus.MustNewConstMetric(c.inUse, prometheus.GaugeValue, float64(stats.InUse))
+	ch <- prometheus.MustNewConstMetric(c.waitCount, prometheus.CounterValue, float64(stats.WaitCount))
+}
+
+// RegisterDBStats registers a DBStatsCollector for db against reg, reusing
+// an already-registered collector instead of panicking if called more than
+// once. A nil reg registers against prometheus.DefaultRegisterer.
+func RegisterDBStats(reg prometheus.Registerer, db *sql.DB) {
+	if reg == nil {
+		reg = prometheus.DefaultRegisterer
+	}
+	registerOrReuse(reg, NewDBStatsCollector(db))
+}
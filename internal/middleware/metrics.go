@@ -0,0 +1,98 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// defaultMetricsBuckets are request-duration histogram buckets, tuned for a
+// sub-second HTTP API rather than prometheus.DefBuckets' wider default
+// spread.
+var defaultMetricsBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// HTTPMetrics holds the request counter/histogram Metrics records into.
+// Construct one with NewHTTPMetrics per registry rather than relying on
+// package-level globals, so tests that stand up more than one server don't
+// hit a duplicate-registration panic.
+type HTTPMetrics struct {
+	requestsTotal   *prometheus.CounterVec
+	requestDuration *prometheus.HistogramVec
+}
+
+// NewHTTPMetrics registers the request counter/histogram against reg,
+// reusing the already-registered collectors instead of panicking if called
+// more than once against the same registry. A nil reg registers against
+// prometheus.DefaultRegisterer.
+func NewHTTPMetrics(reg prometheus.Registerer) *HTTPMetrics {
+	if reg == nil {
+		reg = prometheus.DefaultRegisterer
+	}
+
+	requestsTotal := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "openbpl_http_requests_total",
+		Help: "Total number of HTTP requests handled, labeled by method, route, and status.",
+	}, []string{"method", "route", "status"})
+
+	requestDuration := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "openbpl_http_request_duration_seconds",
+		Help:    "HTTP request duration in seconds, labeled by method, route, and status.",
+		Buckets: defaultMetricsBuckets,
+	}, []string{"method", "route", "status"})
+
+	return &HTTPMetrics{
+		requestsTotal:   registerOrReuse(reg, requestsTotal).(*prometheus.CounterVec),
+		requestDuration: registerOrReuse(reg, requestDuration).(*prometheus.HistogramVec),
+	}
+}
+
+// registerOrReuse registers c against reg, returning whichever collector
+// ends up registered: c itself, or - if something with the same fully
+// qualified name is already registered - that existing collector.
+func registerOrReuse(reg prometheus.Registerer, c prometheus.Collector) prometheus.Collector {
+	if err := reg.Register(c); err != nil {
+		if are, ok := err.(prometheus.AlreadyRegisteredError); ok {
+			return are.ExistingCollector
+		}
+		panic(err)
+	}
+	return c
+}
+
+// Middleware returns HTTP middleware recording request counters/histograms
+// labeled by method, route (the Go 1.22 mux pattern via r.Pattern, falling
+// back to the raw path if the handler wasn't reached through a registered
+// pattern), and status.
+func (m *HTTPMetrics) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		wrapped := &responseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+
+		next.ServeHTTP(wrapped, r)
+
+		route := r.Pattern
+		if route == "" {
+			route = r.URL.Path
+		}
+		status := strconv.Itoa(wrapped.statusCode)
+
+		m.requestsTotal.WithLabelValues(r.Method, route, status).Inc()
+		m.requestDuration.WithLabelValues(r.Method, route, status).Observe(time.Since(start).Seconds())
+	})
+}
+
+// defaultHTTPMetrics backs the package-level Metrics middleware below, so
+// existing callers that just want "the" request metrics - registered once,
+// against the default registry - don't need to construct an HTTPMetrics
+// themselves.
+var defaultHTTPMetrics = NewHTTPMetrics(nil)
+
+// Metrics is HTTP middleware recording request counters/histograms against
+// prometheus.DefaultRegisterer. Construct your own HTTPMetrics via
+// NewHTTPMetrics if you need collectors registered against an isolated
+// registry, e.g. in tests that build multiple servers.
+func Metrics(next http.Handler) http.Handler {
+	return defaultHTTPMetrics.Middleware(next)
+}
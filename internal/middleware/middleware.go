@@ -1,9 +1,16 @@
 package middleware
 
 import (
+	"crypto/subtle"
 	"log"
+	"log/slog"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
+
+	"openbpl/internal/config"
+	"openbpl/internal/logging"
 )
 
 type Middleware func(http.Handler) http.Handler
@@ -12,8 +19,9 @@ type Middleware func(http.Handler) http.Handler
 // This is called "embedding" - we embed http.ResponseWriter to get all its methods
 // Then we override specific methods to add our own behavior
 type responseWriter struct {
-	http.ResponseWriter     // Embedded field - gives us all ResponseWriter methods
-	statusCode          int // Our additional field to track status code
+	http.ResponseWriter       // Embedded field - gives us all ResponseWriter methods
+	statusCode          int   // Our additional field to track status code
+	bytesWritten        int64 // Running total of response body bytes, for AccessLog
 }
 
 func (rw *responseWriter) WriteHeader(code int) {
@@ -21,6 +29,12 @@ func (rw *responseWriter) WriteHeader(code int) {
 	rw.ResponseWriter.WriteHeader(code) // Call the original WriteHeader
 }
 
+func (rw *responseWriter) Write(b []byte) (int, error) {
+	n, err := rw.ResponseWriter.Write(b)
+	rw.bytesWritten += int64(n)
+	return n, err
+}
+
 // This chains middlewares onto a given handler, for example if you
 // want A + B + C on handler h you essentially do  A(B(C(h)))
 // we go in reverse to get the right execution order
@@ -31,39 +45,141 @@ func Chain(handler http.Handler, middlewares ...Middleware) http.Handler {
 	return handler
 }
 
-func Logger(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		start := time.Now()
-		wrappedWriter := &responseWriter{
-			ResponseWriter: w,
-			statusCode:     http.StatusOK,
-		}
-		next.ServeHTTP(wrappedWriter, r)
-		duration := time.Since(start)
-		log.Printf(
-			"%s %s %d %v %s",         // Format string
-			r.Method,                 // HTTP method (GET, POST, etc.)
-			r.URL.Path,               // Request path (/api/users)
-			wrappedWriter.statusCode, // HTTP status code (200, 404, etc.)
-			duration,                 // How long the request took
-			r.RemoteAddr,             // Client IP address
-		)
-	})
+// Logger returns middleware that logs one structured line per request via
+// logger, tagged with the request's correlation ID (see RequestID) so a
+// request's path through downstream storage/enforcer calls can be grepped
+// by that one ID. AccessLog replaces Logger rather than stacking with it -
+// see AccessLog's doc comment - since both would otherwise write a line per
+// request.
+func Logger(logger *slog.Logger) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			wrappedWriter := &responseWriter{
+				ResponseWriter: w,
+				statusCode:     http.StatusOK,
+			}
+			next.ServeHTTP(wrappedWriter, r)
+			duration := time.Since(start)
+			logging.With(r.Context(), logger).Info("request",
+				"method", r.Method,
+				"path", r.URL.Path,
+				"status", wrappedWriter.statusCode,
+				"duration", duration,
+				"remote_addr", r.RemoteAddr,
+			)
+		})
+	}
 }
 
-func CORS(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Access-Control-Allow-Origin", "*")                                // Allow all origins
-		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS") // Allowed HTTP methods
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")     // Allowed headers
-		if r.Method == "OPTIONS" {
-			w.WriteHeader(http.StatusOK) // Return 200 OK
-			return                       // Don't call the next handler - this is just a preflight check
+// CORS returns middleware enforcing cfg's CORS policy: the Origin header is
+// echoed back (rather than a blanket "*") only when it matches one of
+// cfg.AllowedOrigins, which may contain "*", an exact origin, or a wildcard
+// subdomain like "*.example.com". A cross-origin preflight whose Origin
+// doesn't match is rejected outright instead of being allowed through
+// without CORS headers, since browsers would block it client-side anyway
+// and failing fast here surfaces the misconfiguration sooner.
+func CORS(cfg config.CORSConfig) Middleware {
+	maxAge := parseCORSMaxAge(cfg.MaxAge)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("Vary", "Origin")
+
+			origin := r.Header.Get("Origin")
+			if origin == "" {
+				// Same-origin or non-browser request: no CORS headers needed.
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if !originAllowed(origin, cfg.AllowedOrigins) {
+				if r.Method == http.MethodOptions {
+					http.Error(w, "Forbidden: origin not allowed", http.StatusForbidden)
+					return
+				}
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+			if cfg.AllowCredentials {
+				w.Header().Set("Access-Control-Allow-Credentials", "true")
+			}
+			if len(cfg.ExposedHeaders) > 0 {
+				w.Header().Set("Access-Control-Expose-Headers", strings.Join(cfg.ExposedHeaders, ", "))
+			}
+
+			if r.Method != http.MethodOptions {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			w.Header().Set("Access-Control-Allow-Methods", strings.Join(cfg.AllowedMethods, ", "))
+			if requested := r.Header.Get("Access-Control-Request-Headers"); requested != "" {
+				w.Header().Set("Access-Control-Allow-Headers", requested)
+			} else {
+				w.Header().Set("Access-Control-Allow-Headers", strings.Join(cfg.AllowedHeaders, ", "))
+			}
+			if maxAge > 0 {
+				w.Header().Set("Access-Control-Max-Age", strconv.Itoa(int(maxAge.Seconds())))
+			}
+			w.WriteHeader(http.StatusOK)
+		})
+	}
+}
+
+// originAllowed reports whether origin matches one of allowed, which may
+// contain "*", an exact origin, or a wildcard subdomain pattern such as
+// "*.example.com" (matching "https://foo.example.com" but not
+// "https://example.com" itself).
+func originAllowed(origin string, allowed []string) bool {
+	for _, pattern := range allowed {
+		if pattern == "*" || pattern == origin {
+			return true
 		}
+		if strings.HasPrefix(pattern, "*.") && strings.HasSuffix(origin, strings.TrimPrefix(pattern, "*")) {
+			return true
+		}
+	}
+	return false
+}
 
-		// For non-OPTIONS requests, continue to the next handler
-		next.ServeHTTP(w, r)
-	})
+// parseCORSMaxAge parses raw (e.g. "10m") as a duration, returning 0 - which
+// callers treat as "omit Access-Control-Max-Age" - for an empty or invalid
+// value.
+func parseCORSMaxAge(raw string) time.Duration {
+	d, err := time.ParseDuration(raw)
+	if err != nil {
+		return 0
+	}
+	return d
+}
+
+// RequireBearerToken returns middleware that rejects any request whose
+// Authorization header isn't "Bearer <token>" for the given token (e.g. the
+// config's JWTSecret). It checks a single shared token; see the Auth
+// middleware for the fuller API-key/mTLS scheme.
+func RequireBearerToken(token string) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			const prefix = "Bearer "
+
+			header := r.Header.Get("Authorization")
+			if !strings.HasPrefix(header, prefix) {
+				http.Error(w, "Unauthorized", http.StatusUnauthorized)
+				return
+			}
+
+			presented := strings.TrimPrefix(header, prefix)
+			if subtle.ConstantTimeCompare([]byte(presented), []byte(token)) != 1 {
+				http.Error(w, "Unauthorized", http.StatusUnauthorized)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
 }
 
 func Recovery(next http.Handler) http.Handler {
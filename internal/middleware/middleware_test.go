@@ -4,12 +4,18 @@ import (
 	"bytes"
 	"fmt"
 	"log"
+	"log/slog"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"openbpl/internal/config"
 )
 
 func TestChain(t *testing.T) {
@@ -111,10 +117,8 @@ func TestChain(t *testing.T) {
 }
 
 func TestLogger(t *testing.T) {
-	// Capture log output
 	var logBuffer bytes.Buffer
-	log.SetOutput(&logBuffer)
-	defer log.SetOutput(os.Stderr) // Restore default
+	logger := slog.New(slog.NewTextHandler(&logBuffer, nil))
 
 	t.Run("logs successful requests", func(t *testing.T) {
 		logBuffer.Reset()
@@ -124,7 +128,7 @@ func TestLogger(t *testing.T) {
 			w.Write([]byte("success"))
 		})
 
-		loggedHandler := Logger(handler)
+		loggedHandler := Logger(logger)(handler)
 
 		req := httptest.NewRequest("GET", "/api/test", nil)
 		req.RemoteAddr = "192.168.1.1:12345"
@@ -172,7 +176,7 @@ func TestLogger(t *testing.T) {
 					w.WriteHeader(tc.statusCode)
 				})
 
-				loggedHandler := Logger(handler)
+				loggedHandler := Logger(logger)(handler)
 
 				req := httptest.NewRequest("POST", "/test", nil)
 				w := httptest.NewRecorder()
@@ -198,7 +202,7 @@ func TestLogger(t *testing.T) {
 			w.Write([]byte("default status"))
 		})
 
-		loggedHandler := Logger(handler)
+		loggedHandler := Logger(logger)(handler)
 
 		req := httptest.NewRequest("GET", "/test", nil)
 		w := httptest.NewRecorder()
@@ -219,7 +223,7 @@ func TestLogger(t *testing.T) {
 			w.WriteHeader(http.StatusOK)
 		})
 
-		loggedHandler := Logger(handler)
+		loggedHandler := Logger(logger)(handler)
 
 		req := httptest.NewRequest("GET", "/slow", nil)
 		w := httptest.NewRecorder()
@@ -236,90 +240,157 @@ func TestLogger(t *testing.T) {
 	})
 }
 
+func TestMetrics(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	})
+
+	req := httptest.NewRequest("POST", "/api/v1/widgets", nil)
+	req.Pattern = "POST /api/v1/widgets"
+	w := httptest.NewRecorder()
+
+	Metrics(handler).ServeHTTP(w, req)
+
+	got := testutil.ToFloat64(defaultHTTPMetrics.requestsTotal.WithLabelValues("POST", "POST /api/v1/widgets", "201"))
+	if got != 1 {
+		t.Errorf("expected httpRequestsTotal to be 1, got %v", got)
+	}
+}
+
+func TestNewHTTPMetricsIsIdempotentPerRegistry(t *testing.T) {
+	reg := prometheus.NewRegistry()
+
+	first := NewHTTPMetrics(reg)
+	second := NewHTTPMetrics(reg)
+
+	if first.requestsTotal != second.requestsTotal {
+		t.Error("expected a second NewHTTPMetrics against the same registry to reuse the first's counter")
+	}
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	req := httptest.NewRequest("GET", "/widgets", nil)
+	req.Pattern = "GET /widgets"
+
+	second.Middleware(handler).ServeHTTP(httptest.NewRecorder(), req)
+
+	got := testutil.ToFloat64(first.requestsTotal.WithLabelValues("GET", "GET /widgets", "200"))
+	if got != 1 {
+		t.Errorf("expected the shared counter to observe the request, got %v", got)
+	}
+}
+
 func TestCORS(t *testing.T) {
 	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Write([]byte("OK"))
 	})
 
-	corsHandler := CORS(handler)
+	cfg := config.CORSConfig{
+		AllowedOrigins: []string{"https://app.example.com", "*.widgets.example.com"},
+		AllowedMethods: []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
+		AllowedHeaders: []string{"Content-Type", "Authorization"},
+		MaxAge:         "10m",
+	}
+	corsHandler := CORS(cfg)(handler)
 
-	t.Run("sets CORS headers for regular requests", func(t *testing.T) {
+	t.Run("echoes an allowed origin for regular requests", func(t *testing.T) {
 		req := httptest.NewRequest("GET", "/test", nil)
+		req.Header.Set("Origin", "https://app.example.com")
 		w := httptest.NewRecorder()
 
 		corsHandler.ServeHTTP(w, req)
 
-		// Check all CORS headers are set
-		headers := w.Header()
-
-		if headers.Get("Access-Control-Allow-Origin") != "*" {
-			t.Errorf("Expected Allow-Origin *, got %s", headers.Get("Access-Control-Allow-Origin"))
+		if got := w.Header().Get("Access-Control-Allow-Origin"); got != "https://app.example.com" {
+			t.Errorf("expected Allow-Origin to echo the request origin, got %s", got)
 		}
-
-		allowMethods := headers.Get("Access-Control-Allow-Methods")
-		expectedMethods := []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"}
-		for _, method := range expectedMethods {
-			if !strings.Contains(allowMethods, method) {
-				t.Errorf("Allow-Methods should contain %s, got %s", method, allowMethods)
-			}
+		if w.Header().Get("Vary") != "Origin" {
+			t.Error("expected Vary: Origin on every response")
 		}
+		if w.Body.String() != "OK" {
+			t.Errorf("expected body 'OK', got '%s'", w.Body.String())
+		}
+	})
 
-		allowHeaders := headers.Get("Access-Control-Allow-Headers")
-		expectedHeaders := []string{"Content-Type", "Authorization"}
-		for _, header := range expectedHeaders {
-			if !strings.Contains(allowHeaders, header) {
-				t.Errorf("Allow-Headers should contain %s, got %s", header, allowHeaders)
-			}
+	t.Run("matches a wildcard subdomain pattern", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/test", nil)
+		req.Header.Set("Origin", "https://checkout.widgets.example.com")
+		w := httptest.NewRecorder()
+
+		corsHandler.ServeHTTP(w, req)
+
+		if got := w.Header().Get("Access-Control-Allow-Origin"); got != "https://checkout.widgets.example.com" {
+			t.Errorf("expected the subdomain origin to be echoed, got %s", got)
 		}
+	})
+
+	t.Run("omits CORS headers for a same-origin request with no Origin header", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/test", nil)
+		w := httptest.NewRecorder()
 
-		// Should call the next handler
+		corsHandler.ServeHTTP(w, req)
+
+		if w.Header().Get("Access-Control-Allow-Origin") != "" {
+			t.Error("expected no Allow-Origin header when the request carries no Origin")
+		}
 		if w.Body.String() != "OK" {
-			t.Errorf("Expected body 'OK', got '%s'", w.Body.String())
+			t.Error("expected the next handler to still be called")
 		}
 	})
 
-	t.Run("handles OPTIONS preflight requests", func(t *testing.T) {
+	t.Run("rejects a preflight from a disallowed origin", func(t *testing.T) {
 		req := httptest.NewRequest("OPTIONS", "/test", nil)
+		req.Header.Set("Origin", "https://evil.example.org")
 		w := httptest.NewRecorder()
 
 		corsHandler.ServeHTTP(w, req)
 
-		// Should return 200 OK
-		if w.Code != http.StatusOK {
-			t.Errorf("Expected status 200 for OPTIONS, got %d", w.Code)
+		if w.Code != http.StatusForbidden {
+			t.Errorf("expected status 403, got %d", w.Code)
 		}
+	})
+
+	t.Run("handles an allowed preflight request", func(t *testing.T) {
+		req := httptest.NewRequest("OPTIONS", "/test", nil)
+		req.Header.Set("Origin", "https://app.example.com")
+		req.Header.Set("Access-Control-Request-Headers", "X-Custom-Header")
+		w := httptest.NewRecorder()
 
-		// Should NOT call the next handler (body should be empty)
+		corsHandler.ServeHTTP(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected status 200 for an allowed preflight, got %d", w.Code)
+		}
 		if w.Body.String() == "OK" {
-			t.Error("OPTIONS request should not reach the actual handler")
+			t.Error("a preflight should not reach the actual handler")
 		}
 
-		// Should still have CORS headers
-		if w.Header().Get("Access-Control-Allow-Origin") != "*" {
-			t.Error("OPTIONS response should include CORS headers")
+		allowMethods := w.Header().Get("Access-Control-Allow-Methods")
+		for _, method := range cfg.AllowedMethods {
+			if !strings.Contains(allowMethods, method) {
+				t.Errorf("Allow-Methods should contain %s, got %s", method, allowMethods)
+			}
+		}
+		if got := w.Header().Get("Access-Control-Allow-Headers"); got != "X-Custom-Header" {
+			t.Errorf("expected Allow-Headers to reflect the requested headers, got %s", got)
+		}
+		if w.Header().Get("Access-Control-Max-Age") != "600" {
+			t.Errorf("expected Max-Age 600, got %s", w.Header().Get("Access-Control-Max-Age"))
 		}
 	})
 
-	t.Run("works with different HTTP methods", func(t *testing.T) {
-		methods := []string{"GET", "POST", "PUT", "DELETE", "PATCH"}
-
-		for _, method := range methods {
-			t.Run(method, func(t *testing.T) {
-				req := httptest.NewRequest(method, "/test", nil)
-				w := httptest.NewRecorder()
-
-				corsHandler.ServeHTTP(w, req)
+	t.Run("allows a non-credentialed, non-preflight request through a disallowed origin", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/test", nil)
+		req.Header.Set("Origin", "https://evil.example.org")
+		w := httptest.NewRecorder()
 
-				// Should have CORS headers
-				if w.Header().Get("Access-Control-Allow-Origin") != "*" {
-					t.Errorf("CORS headers missing for %s request", method)
-				}
+		corsHandler.ServeHTTP(w, req)
 
-				// Should call the handler (except OPTIONS)
-				if method != "OPTIONS" && w.Body.String() != "OK" {
-					t.Errorf("Handler not called for %s request", method)
-				}
-			})
+		if w.Header().Get("Access-Control-Allow-Origin") != "" {
+			t.Error("expected no Allow-Origin header for a disallowed origin")
+		}
+		if w.Body.String() != "OK" {
+			t.Error("expected the next handler to still run; the browser enforces the CORS policy, not the server")
 		}
 	})
 }
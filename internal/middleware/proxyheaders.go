@@ -0,0 +1,146 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+	"strings"
+
+	"openbpl/internal/config"
+)
+
+// ProxyHeaders returns middleware that rewrites r.RemoteAddr, r.URL.Scheme,
+// and r.Host from X-Forwarded-For/X-Real-IP/Forwarded and
+// X-Forwarded-Proto/Host, but only when the direct peer's address is in one
+// of cfg's TrustedCIDRs - a request from anywhere else has these headers
+// ignored entirely, since they'd otherwise let any client spoof its own IP.
+// It should sit outermost in the chain, ahead of Logger/AccessLog/Auth/
+// RateLimit, so everything downstream sees the real client.
+func ProxyHeaders(cfg config.ProxyHeadersConfig) Middleware {
+	trusted := parseTrustedCIDRs(cfg.TrustedCIDRs)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !cfg.Enabled || !peerTrusted(r.RemoteAddr, trusted) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if clientIP := forwardedClientIP(r, trusted); clientIP != "" {
+				r.RemoteAddr = net.JoinHostPort(clientIP, "0")
+			}
+			if proto := r.Header.Get("X-Forwarded-Proto"); proto != "" {
+				r.URL.Scheme = proto
+			}
+			if host := r.Header.Get("X-Forwarded-Host"); host != "" {
+				r.Host = host
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// parseTrustedCIDRs parses raw CIDRs, silently skipping any that don't
+// parse; Config.validate is what actually rejects a bad CIDR, so by the
+// time ProxyHeaders runs in production every entry is expected to be valid.
+func parseTrustedCIDRs(raw []string) []*net.IPNet {
+	nets := make([]*net.IPNet, 0, len(raw))
+	for _, cidr := range raw {
+		if _, ipNet, err := net.ParseCIDR(cidr); err == nil {
+			nets = append(nets, ipNet)
+		}
+	}
+	return nets
+}
+
+// peerTrusted reports whether remoteAddr's host is within one of trusted.
+func peerTrusted(remoteAddr string, trusted []*net.IPNet) bool {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+	return ipTrusted(host, trusted)
+}
+
+// ipTrusted reports whether host - a bare IP, no port - is within one of
+// trusted.
+func ipTrusted(host string, trusted []*net.IPNet) bool {
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return false
+	}
+
+	for _, ipNet := range trusted {
+		if ipNet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// forwardedClientIP returns the originating client IP from, in order of
+// preference, X-Forwarded-For, X-Real-IP, or RFC 7239 Forwarded. Returns ""
+// if none of the three are present.
+//
+// X-Forwarded-For and Forwarded are lists that each hop *appends* to as a
+// request passes through, so the rightmost entry is the one our own trusted
+// edge actually observed; everything left of it is whatever the client (or
+// an untrusted intermediary) claimed and cannot be trusted. Both are walked
+// from the right, skipping entries inside trusted, and the first entry
+// that isn't is returned - so a client connecting straight through the LB
+// can't spoof this by prepending a forged IP of its own.
+func forwardedClientIP(r *http.Request, trusted []*net.IPNet) string {
+	if xff := r.Header.Get("X-Forwarded-For"); xff != "" {
+		hops := strings.Split(xff, ",")
+		for i := len(hops) - 1; i >= 0; i-- {
+			if hop := strings.TrimSpace(hops[i]); !ipTrusted(hop, trusted) {
+				return hop
+			}
+		}
+		return ""
+	}
+
+	if realIP := r.Header.Get("X-Real-IP"); realIP != "" {
+		return strings.TrimSpace(realIP)
+	}
+
+	if forwarded := r.Header.Get("Forwarded"); forwarded != "" {
+		return parseForwardedFor(forwarded, trusted)
+	}
+
+	return ""
+}
+
+// parseForwardedFor extracts the "for=" parameter from the rightmost
+// element of an RFC 7239 Forwarded header that isn't inside trusted, e.g.
+// `for=10.0.0.1, for=203.0.113.9;proto=https` with trusted covering
+// 10.0.0.1 -> "203.0.113.9". IPv6 addresses are quoted and bracketed per
+// the RFC ("for=\"[2001:db8::1]\"") and unwrapped here.
+func parseForwardedFor(header string, trusted []*net.IPNet) string {
+	elements := strings.Split(header, ",")
+	for i := len(elements) - 1; i >= 0; i-- {
+		value := forwardedForValue(elements[i])
+		if value != "" && !ipTrusted(value, trusted) {
+			return value
+		}
+	}
+	return ""
+}
+
+// forwardedForValue extracts the "for=" parameter from a single
+// semicolon-delimited element of an RFC 7239 Forwarded header, or "" if the
+// element has none.
+func forwardedForValue(element string) string {
+	for _, part := range strings.Split(element, ";") {
+		part = strings.TrimSpace(part)
+		if !strings.HasPrefix(strings.ToLower(part), "for=") {
+			continue
+		}
+		value := strings.TrimPrefix(part, part[:4])
+		value = strings.Trim(value, `"`)
+		value = strings.TrimPrefix(value, "[")
+		value = strings.TrimSuffix(value, "]")
+		return value
+	}
+	return ""
+}
@@ -0,0 +1,102 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"openbpl/internal/config"
+)
+
+func TestProxyHeadersRewritesFromTrustedPeer(t *testing.T) {
+	cfg := config.ProxyHeadersConfig{
+		Enabled:      true,
+		TrustedCIDRs: []string{"127.0.0.1/32", "10.0.0.0/8"},
+	}
+
+	var gotRemoteAddr, gotScheme, gotHost string
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotRemoteAddr = r.RemoteAddr
+		gotScheme = r.URL.Scheme
+		gotHost = r.Host
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "http://lb.internal/widgets", nil)
+	req.RemoteAddr = "127.0.0.1:54321"
+	// 10.0.0.1 is the trusted internal LB that appended itself; the
+	// client-claimed 203.0.113.9 to its left is the one that should win.
+	req.Header.Set("X-Forwarded-For", "203.0.113.9, 10.0.0.1")
+	req.Header.Set("X-Forwarded-Proto", "https")
+	req.Header.Set("X-Forwarded-Host", "example.com")
+
+	ProxyHeaders(cfg)(next).ServeHTTP(httptest.NewRecorder(), req)
+
+	if host, _, _ := net.SplitHostPort(gotRemoteAddr); host != "203.0.113.9" {
+		t.Errorf("expected RemoteAddr host 203.0.113.9, got %q", gotRemoteAddr)
+	}
+	if gotScheme != "https" {
+		t.Errorf("expected scheme https, got %q", gotScheme)
+	}
+	if gotHost != "example.com" {
+		t.Errorf("expected host example.com, got %q", gotHost)
+	}
+}
+
+func TestProxyHeadersIgnoresUntrustedPeer(t *testing.T) {
+	cfg := config.ProxyHeadersConfig{
+		Enabled:      true,
+		TrustedCIDRs: []string{"127.0.0.1/32"},
+	}
+
+	var gotRemoteAddr string
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotRemoteAddr = r.RemoteAddr
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/widgets", nil)
+	req.RemoteAddr = "198.51.100.7:1234"
+	req.Header.Set("X-Forwarded-For", "203.0.113.9")
+
+	ProxyHeaders(cfg)(next).ServeHTTP(httptest.NewRecorder(), req)
+
+	if gotRemoteAddr != "198.51.100.7:1234" {
+		t.Errorf("expected RemoteAddr untouched, got %q", gotRemoteAddr)
+	}
+}
+
+func TestForwardedClientIPFallsBackToForwardedHeader(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Forwarded", `for="[2001:db8::1]";proto=https`)
+
+	if got := forwardedClientIP(req, nil); got != "2001:db8::1" {
+		t.Errorf("expected 2001:db8::1, got %q", got)
+	}
+}
+
+func TestForwardedClientIPRejectsSpoofedLeftmostHop(t *testing.T) {
+	_, trustedLB, _ := net.ParseCIDR("10.0.0.0/8")
+	trusted := []*net.IPNet{trustedLB}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	// The attacker connects straight through the trusted LB and sends a
+	// forged leftmost entry; the LB appends the attacker's real peer
+	// address, which is the rightmost, untrusted entry and must win.
+	req.Header.Set("X-Forwarded-For", "203.0.113.9, 198.51.100.23")
+
+	if got := forwardedClientIP(req, trusted); got != "198.51.100.23" {
+		t.Errorf("expected the untrusted rightmost hop 198.51.100.23, got %q", got)
+	}
+}
+
+func TestForwardedClientIPReturnsEmptyWhenAllHopsTrusted(t *testing.T) {
+	_, trustedLB, _ := net.ParseCIDR("10.0.0.0/8")
+	trusted := []*net.IPNet{trustedLB}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-Forwarded-For", "10.0.0.2, 10.0.0.1")
+
+	if got := forwardedClientIP(req, trusted); got != "" {
+		t.Errorf("expected no usable client IP when every hop is trusted, got %q", got)
+	}
+}
@@ -0,0 +1,135 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// bucket is a single key's token bucket: up to capacity tokens, refilled at
+// a fixed rate and lazily topped up on every Allow call rather than on a
+// ticker, so an idle key costs nothing between requests.
+type bucket struct {
+	tokens     float64
+	lastRefill time.Time
+}
+
+// idleTTL is how long a bucket may go unused before NewRateLimiter's
+// background sweep evicts it. Set well above any realistic
+// capacity/rps refill time, so eviction never races a client that's still
+// active.
+const idleTTL = 10 * time.Minute
+
+// sweepInterval is how often the background sweep checks for idle buckets.
+const sweepInterval = time.Minute
+
+// RateLimiter hands out one independent token bucket per key. It's the type
+// RateLimit builds its middleware on; exported so callers that want to
+// share a limiter across more than one middleware instance can construct
+// it directly.
+type RateLimiter struct {
+	mu       sync.Mutex
+	rps      float64
+	capacity float64
+	buckets  map[string]*bucket
+}
+
+// NewRateLimiter creates a limiter allowing rps requests per second per key,
+// with bursts up to capacity tokens. It starts a background goroutine that
+// evicts buckets idle for longer than idleTTL, so a limiter fronting a
+// public endpoint - keyed by client IP by default - doesn't grow one bucket
+// per distinct IP forever.
+func NewRateLimiter(rps, capacity int) *RateLimiter {
+	l := &RateLimiter{
+		rps:      float64(rps),
+		capacity: float64(capacity),
+		buckets:  make(map[string]*bucket),
+	}
+	go l.sweepIdle(sweepInterval)
+	return l
+}
+
+// EvictIdle removes every bucket last used before cutoff.
+func (l *RateLimiter) EvictIdle(cutoff time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for key, b := range l.buckets {
+		if b.lastRefill.Before(cutoff) {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// sweepIdle calls EvictIdle once per interval, for the life of the process.
+func (l *RateLimiter) sweepIdle(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		l.EvictIdle(time.Now().Add(-idleTTL))
+	}
+}
+
+// Allow reports whether a request for key may proceed right now, consuming
+// one token from its bucket if so.
+func (l *RateLimiter) Allow(key string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: l.capacity, lastRefill: now}
+		l.buckets[key] = b
+	}
+
+	elapsed := now.Sub(b.lastRefill).Seconds()
+	b.tokens = minFloat(l.capacity, b.tokens+elapsed*l.rps)
+	b.lastRefill = now
+
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+func minFloat(a, b float64) float64 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// RateLimit returns middleware enforcing a token-bucket limit of rps
+// requests per second, bursting up to burst, independently per
+// keyFn(request). keyFn defaults to the request's remote IP (stripped of
+// port) when nil.
+func RateLimit(rps, burst int, keyFn func(*http.Request) string) Middleware {
+	limiter := NewRateLimiter(rps, burst)
+	if keyFn == nil {
+		keyFn = remoteIPKey
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !limiter.Allow(keyFn(r)) {
+				http.Error(w, "Too Many Requests", http.StatusTooManyRequests)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// remoteIPKey is the default RateLimit key: the request's remote IP with
+// its port stripped, falling back to the raw RemoteAddr if it has none.
+func remoteIPKey(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
@@ -0,0 +1,81 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRateLimiterAllow(t *testing.T) {
+	limiter := NewRateLimiter(1, 2)
+
+	if !limiter.Allow("client-a") {
+		t.Fatal("expected first request to be allowed")
+	}
+	if !limiter.Allow("client-a") {
+		t.Fatal("expected second request to be allowed (within burst)")
+	}
+	if limiter.Allow("client-a") {
+		t.Fatal("expected third request to be rejected once the burst is exhausted")
+	}
+
+	if !limiter.Allow("client-b") {
+		t.Error("expected a different key to have its own, unexhausted bucket")
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	handler := RateLimit(1, 1, func(r *http.Request) string { return "fixed-key" })(next)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected status 429, got %d", rec.Code)
+	}
+}
+
+func TestRateLimiterEvictIdleRemovesOnlyStaleBuckets(t *testing.T) {
+	limiter := NewRateLimiter(1, 2)
+
+	limiter.Allow("stale")
+	limiter.Allow("fresh")
+
+	// Backdate "stale" as if it hasn't been seen since well before idleTTL;
+	// "fresh" keeps its real, just-set lastRefill.
+	limiter.mu.Lock()
+	limiter.buckets["stale"].lastRefill = time.Now().Add(-2 * idleTTL)
+	limiter.mu.Unlock()
+
+	limiter.EvictIdle(time.Now().Add(-idleTTL))
+
+	limiter.mu.Lock()
+	_, staleStillPresent := limiter.buckets["stale"]
+	_, freshStillPresent := limiter.buckets["fresh"]
+	limiter.mu.Unlock()
+
+	if staleStillPresent {
+		t.Error("expected the stale bucket to be evicted")
+	}
+	if !freshStillPresent {
+		t.Error("expected the fresh bucket to survive eviction")
+	}
+}
+
+func TestRemoteIPKey(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = "203.0.113.5:54321"
+
+	if key := remoteIPKey(req); key != "203.0.113.5" {
+		t.Errorf("expected 203.0.113.5, got %s", key)
+	}
+}
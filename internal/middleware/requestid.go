@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+
+	"openbpl/internal/logging"
+)
+
+// RequestID returns middleware that ensures every request carries an
+// X-Request-Id - generating one if the client (or a proxy ahead of us)
+// didn't set one - and attaches it to the request's context so
+// logging.With tags every downstream log line with it, letting a single
+// request's path through storage/enforcer calls be grepped by that one ID.
+// It must sit upstream of Logger (and anything else that logs via
+// logging.With) for the tagging to take effect. Auth already requires this
+// header for its own replay protection, so RequestID should sit upstream
+// of Auth too.
+func RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get("X-Request-Id")
+		if id == "" {
+			id = generateRequestID()
+			r.Header.Set("X-Request-Id", id)
+		}
+		w.Header().Set("X-Request-Id", id)
+		next.ServeHTTP(w, r.WithContext(logging.WithRequestID(r.Context(), id)))
+	})
+}
+
+// generateRequestID returns a random 32-character hex-encoded identifier.
+func generateRequestID() string {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		// crypto/rand.Read failing is effectively unrecoverable on any
+		// real system - fall back to an all-zero ID rather than panicking
+		// mid-request.
+		return "00000000000000000000000000000000"
+	}
+	return hex.EncodeToString(buf)
+}
@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"openbpl/internal/logging"
+)
+
+func TestRequestIDGeneratesWhenAbsent(t *testing.T) {
+	var gotID string
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotID, _ = logging.FromContextRequestID(r.Context())
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/widgets", nil)
+	w := httptest.NewRecorder()
+
+	RequestID(next).ServeHTTP(w, req)
+
+	if gotID == "" {
+		t.Fatal("expected a request ID to be attached to the context")
+	}
+	if w.Header().Get("X-Request-Id") != gotID {
+		t.Errorf("expected response header X-Request-Id %q, got %q", gotID, w.Header().Get("X-Request-Id"))
+	}
+}
+
+func TestRequestIDPreservesExisting(t *testing.T) {
+	var gotID string
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotID, _ = logging.FromContextRequestID(r.Context())
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/widgets", nil)
+	req.Header.Set("X-Request-Id", "caller-supplied-id")
+	w := httptest.NewRecorder()
+
+	RequestID(next).ServeHTTP(w, req)
+
+	if gotID != "caller-supplied-id" {
+		t.Errorf("expected the caller-supplied request ID to be preserved, got %q", gotID)
+	}
+	if w.Header().Get("X-Request-Id") != "caller-supplied-id" {
+		t.Errorf("expected response header to echo the caller-supplied ID, got %q", w.Header().Get("X-Request-Id"))
+	}
+}
+
+func TestRequestIDsAreUnique(t *testing.T) {
+	ids := make(map[string]bool)
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, _ := logging.FromContextRequestID(r.Context())
+		ids[id] = true
+	})
+
+	for i := 0; i < 10; i++ {
+		req := httptest.NewRequest(http.MethodGet, "/widgets", nil)
+		RequestID(next).ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	if len(ids) != 10 {
+		t.Errorf("expected 10 unique request IDs, got %d", len(ids))
+	}
+}
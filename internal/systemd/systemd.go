@@ -0,0 +1,56 @@
+// Package systemd wraps sd_notify so OpenBPL can participate in a
+// Type=notify systemd unit's readiness and watchdog protocol. Every
+// function here is a no-op, zero-dep at runtime when NOTIFY_SOCKET isn't
+// set - i.e. when not actually running under systemd.
+package systemd
+
+import (
+	"context"
+	"time"
+
+	"github.com/coreos/go-systemd/v22/daemon"
+)
+
+// NotifyReady tells systemd the service has finished starting.
+func NotifyReady() error {
+	_, err := daemon.SdNotify(false, daemon.SdNotifyReady)
+	return err
+}
+
+// NotifyStopping tells systemd the service is shutting down, so it doesn't
+// wait out the unit's full TimeoutStopSec before considering the stop a
+// failure.
+func NotifyStopping() error {
+	_, err := daemon.SdNotify(false, daemon.SdNotifyStopping)
+	return err
+}
+
+// RunWatchdog pings systemd's watchdog at half the unit's WatchdogSec,
+// for as long as healthy returns nil, until ctx is done. It's a no-op for
+// a unit without WatchdogSec= set. A failing healthy check withholds the
+// ping rather than notifying systemd directly - systemd's own watchdog
+// timeout, not this health check, is what decides whether to restart the
+// unit.
+func RunWatchdog(ctx context.Context, healthy func() error) error {
+	interval, err := daemon.SdWatchdogEnabled(false)
+	if err != nil || interval == 0 {
+		return err
+	}
+
+	ticker := time.NewTicker(interval / 2)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if healthy() != nil {
+				continue
+			}
+			if _, err := daemon.SdNotify(false, daemon.SdNotifyWatchdog); err != nil {
+				return err
+			}
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package systemd
+
+import (
+	"context"
+	"net"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNotifyReadySendsReadyDatagram(t *testing.T) {
+	conn := listenNotifySocket(t)
+	defer conn.Close()
+
+	if err := NotifyReady(); err != nil {
+		t.Fatalf("NotifyReady returned error: %v", err)
+	}
+
+	msg := readDatagram(t, conn)
+	if !strings.Contains(msg, "READY=1") {
+		t.Errorf("expected READY=1 in datagram, got %q", msg)
+	}
+}
+
+func TestNotifyStoppingSendsStoppingDatagram(t *testing.T) {
+	conn := listenNotifySocket(t)
+	defer conn.Close()
+
+	if err := NotifyStopping(); err != nil {
+		t.Fatalf("NotifyStopping returned error: %v", err)
+	}
+
+	msg := readDatagram(t, conn)
+	if !strings.Contains(msg, "STOPPING=1") {
+		t.Errorf("expected STOPPING=1 in datagram, got %q", msg)
+	}
+}
+
+func TestRunWatchdogPingsWhileHealthy(t *testing.T) {
+	conn := listenNotifySocket(t)
+	defer conn.Close()
+
+	t.Setenv("WATCHDOG_USEC", "100000") // 100ms
+
+	ctx, cancel := context.WithTimeout(context.Background(), 250*time.Millisecond)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() { done <- RunWatchdog(ctx, func() error { return nil }) }()
+
+	msg := readDatagram(t, conn)
+	if !strings.Contains(msg, "WATCHDOG=1") {
+		t.Errorf("expected WATCHDOG=1 in datagram, got %q", msg)
+	}
+
+	if err := <-done; err != nil {
+		t.Errorf("expected RunWatchdog to return cleanly on ctx cancellation, got %v", err)
+	}
+}
+
+func TestRunWatchdogWithholdsPingWhenUnhealthy(t *testing.T) {
+	conn := listenNotifySocket(t)
+	defer conn.Close()
+
+	t.Setenv("WATCHDOG_USEC", "50000") // 50ms
+
+	ctx, cancel := context.WithTimeout(context.Background(), 150*time.Millisecond)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() { done <- RunWatchdog(ctx, func() error { return errUnhealthy }) }()
+	<-done
+
+	conn.SetReadDeadline(time.Now().Add(10 * time.Millisecond))
+	buf := make([]byte, 1024)
+	if _, err := conn.Read(buf); err == nil {
+		t.Error("expected no watchdog datagram while the health check keeps failing")
+	}
+}
+
+func TestRunWatchdogIsNoOpWithoutWatchdogUsec(t *testing.T) {
+	t.Setenv("NOTIFY_SOCKET", "")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if err := RunWatchdog(ctx, func() error { return nil }); err != nil {
+		t.Fatalf("expected RunWatchdog to no-op cleanly, got %v", err)
+	}
+}
+
+var errUnhealthy = &testError{"source is unhealthy"}
+
+type testError struct{ msg string }
+
+func (e *testError) Error() string { return e.msg }
+
+func listenNotifySocket(t *testing.T) *net.UnixConn {
+	t.Helper()
+
+	sockPath := filepath.Join(t.TempDir(), "notify.sock")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: sockPath, Net: "unixgram"})
+	if err != nil {
+		t.Fatalf("failed to listen on notify socket: %v", err)
+	}
+	t.Setenv("NOTIFY_SOCKET", sockPath)
+	return conn
+}
+
+func readDatagram(t *testing.T, conn *net.UnixConn) string {
+	t.Helper()
+
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	buf := make([]byte, 1024)
+	n, err := conn.Read(buf)
+	if err != nil {
+		t.Fatalf("failed to read datagram: %v", err)
+	}
+	return string(buf[:n])
+}
@@ -4,13 +4,58 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"log"
+	"math/rand"
 	"strings"
 	"time"
 
 	"github.com/gorilla/websocket"
+
+	"openbpl/pkg/core/metrics"
+)
+
+const (
+	// certstreamBaseBackoff and certstreamMaxBackoff bound the jittered
+	// exponential backoff between reconnect attempts.
+	certstreamBaseBackoff = 1 * time.Second
+	certstreamMaxBackoff  = 60 * time.Second
+
+	// certstreamDegradeAfter is how many consecutive reconnect failures
+	// it takes before Health() reports the source unhealthy. A handful of
+	// transient drops shouldn't flip it - certstream.calidog.io resets
+	// connections periodically under normal operation.
+	certstreamDegradeAfter = 5
+
+	// certstreamReadTimeout bounds a single blocked read so a silent
+	// connection (no messages, no TCP-level failure) gets noticed instead
+	// of hanging forever.
+	certstreamReadTimeout = 60 * time.Second
+
+	// certstreamEventAgeInterval is how often the idle-between-events
+	// goroutine refreshes source_last_event_age_seconds.
+	certstreamEventAgeInterval = 10 * time.Second
 )
 
+func init() {
+	RegisterSource("certstream", newCertstreamSourceFromParams)
+}
+
+// newCertstreamSourceFromParams lets certstream be configured declaratively
+// via Monitoring.Sources.Additional, alongside its first-class
+// Monitoring.Sources.Certstream config used by initializeSources.
+func newCertstreamSourceFromParams(params map[string]interface{}) (Source, error) {
+	url, _ := params["url"].(string)
+	if url == "" {
+		return nil, fmt.Errorf("missing required param %q", "url")
+	}
+
+	keywords, err := stringSlice(params, "keywords")
+	if err != nil {
+		return nil, err
+	}
+
+	return &CertstreamSource{URL: url, Keywords: keywords}, nil
+}
+
 // CertstreamEntry represents a certificate transparency log entry
 type CertstreamEntry struct {
 	MessageType string `json:"message_type"`
@@ -27,66 +72,156 @@ type CertstreamEntry struct {
 	} `json:"data"`
 }
 
-// Start begins monitoring certstream and sends events to the channel
+// Start begins monitoring certstream and sends events to the channel. A
+// failed or dropped connection is retried with jittered exponential
+// backoff rather than taking the pipeline down; Health() starts reporting
+// an error after certstreamDegradeAfter consecutive failures so Engine.Run
+// can mark this source degraded.
 func (s *CertstreamSource) Start(ctx context.Context, events chan<- Event) error {
-	log.Printf("🔌 Connecting to certstream: %s", s.URL)
+	s.stopCh = make(chan struct{})
+	s.logger().Info("connecting to certstream", "url", s.URL)
 
+	go s.reportEventAge(ctx)
+
+	consecutiveFailures := 0
 	for {
 		select {
 		case <-ctx.Done():
-			log.Printf("🛑 Certstream source stopped")
+			s.logger().Info("certstream source stopped")
+			return nil
+		case <-s.stopCh:
+			s.logger().Info("certstream source stopped")
 			return nil
 		default:
-			if err := s.connect(ctx, events); err != nil {
-				log.Printf("❌ Certstream connection failed: %v", err)
-				log.Printf("🔄 Reconnecting in 5 seconds...")
-
-				// Wait before reconnecting
-				select {
-				case <-ctx.Done():
-					return nil
-				case <-time.After(5 * time.Second):
-					continue
-				}
+		}
+
+		connected, err := s.connect(ctx, events)
+		if connected {
+			consecutiveFailures = 0
+			s.setHealth(nil)
+		}
+		if err != nil {
+			consecutiveFailures++
+			metrics.SourceReconnects.WithLabelValues(s.Name()).Inc()
+
+			if consecutiveFailures >= certstreamDegradeAfter {
+				s.setHealth(fmt.Errorf("%d consecutive reconnect failures: %w", consecutiveFailures, err))
 			}
+			// A single WARN at the point the source actually goes
+			// degraded, not one per attempt - Engine.Run logs its own
+			// transition message from Health(), so this just gives the
+			// source's own log a marker to grep for.
+			if consecutiveFailures == certstreamDegradeAfter {
+				s.logger().Warn("certstream degraded after repeated reconnect failures", "attempts", consecutiveFailures, "error", err)
+			} else {
+				s.logger().Error("certstream connection failed", "error", err, "attempt", consecutiveFailures)
+			}
+
+			delay := reconnectBackoff(consecutiveFailures)
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-s.stopCh:
+				return nil
+			case <-time.After(delay):
+			}
+			continue
 		}
+
+		consecutiveFailures = 0
+		s.setHealth(nil)
+	}
+}
+
+// reconnectBackoff returns a jittered exponential backoff for the given
+// attempt number (1-indexed), doubling from certstreamBaseBackoff up to
+// certstreamMaxBackoff.
+func reconnectBackoff(attempt int) time.Duration {
+	if attempt > 7 {
+		attempt = 7 // 1s << 6 == 64s, already past certstreamMaxBackoff
+	}
+	backoff := certstreamBaseBackoff << uint(attempt-1)
+	if backoff > certstreamMaxBackoff {
+		backoff = certstreamMaxBackoff
 	}
+	return backoff/2 + time.Duration(rand.Int63n(int64(backoff)/2+1))
 }
 
-func (s *CertstreamSource) connect(ctx context.Context, events chan<- Event) error {
-	// Connect to certstream WebSocket
+// connect dials certstream and reads from it until the connection fails or
+// the source is stopped. connected reports whether the dial succeeded.
+func (s *CertstreamSource) connect(ctx context.Context, events chan<- Event) (connected bool, err error) {
 	dialer := websocket.DefaultDialer
-	conn, _, err := dialer.Dial(s.URL, nil)
+	conn, _, err := dialer.DialContext(ctx, s.URL, nil)
 	if err != nil {
-		return fmt.Errorf("failed to connect to certstream: %w", err)
+		return false, fmt.Errorf("failed to connect to certstream: %w", err)
 	}
-	defer conn.Close()
+	s.setConn(conn)
+	defer func() {
+		s.setConn(nil)
+		conn.Close()
+	}()
 
-	log.Printf("✅ Connected to certstream")
-	log.Printf("🔍 Monitoring keywords: %v", s.Keywords)
+	s.logger().Info("connected to certstream", "keywords", s.Keywords)
 
-	// Set read deadline for periodic checks
-	conn.SetReadDeadline(time.Now().Add(60 * time.Second))
+	// Force any currently-blocked read to return as soon as ctx is done,
+	// rather than waiting up to certstreamReadTimeout for it to notice on
+	// its own.
+	watchDone := make(chan struct{})
+	defer close(watchDone)
+	go func() {
+		select {
+		case <-ctx.Done():
+			s.SetReadDeadline(time.Unix(0, 0))
+		case <-watchDone:
+		}
+	}()
+
+	s.SetReadDeadline(time.Now().Add(certstreamReadTimeout))
 
 	for {
 		select {
 		case <-ctx.Done():
-			return nil
+			return true, nil
+		case <-s.stopCh:
+			return true, nil
 		default:
-			// Read message from certstream
-			_, message, err := conn.ReadMessage()
-			if err != nil {
-				return fmt.Errorf("failed to read from certstream: %w", err)
-			}
+		}
+
+		_, message, err := conn.ReadMessage()
+		if err != nil {
+			return true, fmt.Errorf("failed to read from certstream: %w", err)
+		}
+		metrics.CertstreamMessagesReceived.Inc()
 
-			// Reset read deadline
-			conn.SetReadDeadline(time.Now().Add(60 * time.Second))
+		s.SetReadDeadline(time.Now().Add(certstreamReadTimeout))
 
-			// Process the certificate entry
-			if err := s.processCertEntry(message, events); err != nil {
-				log.Printf("⚠️ Failed to process cert entry: %v", err)
-				// Continue processing other entries
+		if err := s.processCertEntry(message, events); err != nil {
+			s.logger().Warn("failed to process cert entry", "error", err)
+		}
+	}
+}
+
+// reportEventAge keeps source_last_event_age_seconds fresh between
+// events; touchLastEvent sets it to zero the instant one arrives, but the
+// gauge would otherwise go stale while the source is idle.
+func (s *CertstreamSource) reportEventAge(ctx context.Context) {
+	ticker := time.NewTicker(certstreamEventAgeInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.stopCh:
+			return
+		case <-ticker.C:
+			s.mu.Lock()
+			last := s.lastEventAt
+			s.mu.Unlock()
+			if last.IsZero() {
+				continue
 			}
+			metrics.SourceLastEventAge.WithLabelValues(s.Name()).Set(time.Since(last).Seconds())
 		}
 	}
 }
@@ -101,9 +236,11 @@ func (s *CertstreamSource) processCertEntry(message []byte, events chan<- Event)
 	if entry.MessageType != "certificate_update" {
 		return nil
 	}
+	metrics.CertstreamEntriesParsed.Inc()
 
 	// Extract domains from the certificate
 	domains := s.extractDomains(&entry)
+	metrics.DomainsExtracted.Add(float64(len(domains)))
 
 	// Check each domain against our keywords
 	for _, domain := range domains {
@@ -128,9 +265,11 @@ func (s *CertstreamSource) processCertEntry(message []byte, events chan<- Event)
 			// Send event to processing pipeline
 			select {
 			case events <- event:
-				log.Printf("🆕 New certificate: %s (matched: %v)", domain, event.Metadata["matched_keywords"])
+				s.touchLastEvent()
+				s.logger().Info("new certificate", "domain", domain, "matched_keywords", event.Metadata["matched_keywords"])
 			case <-time.After(1 * time.Second):
-				log.Printf("⚠️ Event channel full, dropping certificate: %s", domain)
+				metrics.EventChannelDrops.WithLabelValues(s.Name()).Inc()
+				s.logger().Warn("event channel full, dropping certificate", "domain", domain)
 			}
 		}
 	}
@@ -0,0 +1,50 @@
+package core
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+var errFakeConnect = errors.New("fake connect failure")
+
+func TestReconnectBackoffGrowsAndCaps(t *testing.T) {
+	prev := time.Duration(0)
+	for attempt := 1; attempt <= 10; attempt++ {
+		backoff := reconnectBackoff(attempt)
+		if backoff <= 0 {
+			t.Fatalf("attempt %d: expected positive backoff, got %v", attempt, backoff)
+		}
+		if backoff > certstreamMaxBackoff {
+			t.Errorf("attempt %d: backoff %v exceeds cap %v", attempt, backoff, certstreamMaxBackoff)
+		}
+		prev = backoff
+	}
+	if prev > certstreamMaxBackoff {
+		t.Errorf("expected backoff to stay capped at %v for high attempt counts, got %v", certstreamMaxBackoff, prev)
+	}
+	if first := reconnectBackoff(1); first > certstreamBaseBackoff {
+		t.Errorf("expected the first backoff to be at most %v, got %v", certstreamBaseBackoff, first)
+	}
+	if last := reconnectBackoff(10); last < certstreamMaxBackoff/2 {
+		t.Errorf("expected backoff to grow to within jitter of the cap %v, got %v", certstreamMaxBackoff, last)
+	}
+}
+
+func TestCertstreamSourceHealthReportsSetHealth(t *testing.T) {
+	s := &CertstreamSource{URL: "wss://example.invalid"}
+
+	if err := s.Health(); err != nil {
+		t.Fatalf("expected a fresh source to be healthy, got %v", err)
+	}
+
+	s.setHealth(errFakeConnect)
+	if err := s.Health(); err != errFakeConnect {
+		t.Errorf("expected Health() to report the error passed to setHealth, got %v", err)
+	}
+
+	s.setHealth(nil)
+	if err := s.Health(); err != nil {
+		t.Errorf("expected Health() to clear after setHealth(nil), got %v", err)
+	}
+}
@@ -0,0 +1,173 @@
+// pkg/core/diagnostics.go
+package core
+
+import (
+	"database/sql"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+	"openbpl/internal/config"
+	"openbpl/internal/diagnostics"
+	"openbpl/internal/logging"
+)
+
+// recentLogLines is how many of the most recent log.Printf lines (captured
+// via logging.CaptureRecent) Diagnostics includes in the bundle.
+const recentLogLines = 200
+
+const redacted = "REDACTED"
+
+// buildInfo holds the version/commit/build-time identifiers Diagnostics
+// reports, set via SetBuildInfo. It's a separate type from the fields
+// directly on Engine since NewEngine has no way to know them - they live
+// in package main as linker-set vars.
+type buildInfo struct {
+	version, commit, buildTime string
+}
+
+// SetBuildInfo records the binary's version/commit/build-time identifiers
+// for Diagnostics to report. Callers that never set this (e.g. tests) get
+// an empty version block in the bundle rather than an error.
+func (e *Engine) SetBuildInfo(version, commit, buildTime string) {
+	e.build = buildInfo{version: version, commit: commit, buildTime: buildTime}
+}
+
+// Diagnostics builds a redacted support bundle for this engine: resolved
+// config, Go runtime stats, a goroutine dump, storage health, and source
+// status. It satisfies internal/handlers.Diagnostician, so the HTTP server
+// can surface the same bundle the CLI's "openbpl diagnostics" subcommand
+// produces.
+func (e *Engine) Diagnostics() ([]byte, error) {
+	redactedConfig, err := redactConfig(e.cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to redact config for diagnostics: %w", err)
+	}
+
+	info := diagnostics.Info{
+		Component:       "openbpl-engine",
+		Version:         e.build.version,
+		Commit:          e.build.commit,
+		BuildTime:       e.build.buildTime,
+		Config:          redactedConfig,
+		DB:              storageDB(e.storage),
+		ComponentStatus: e.componentStatus(),
+		LogLines:        logging.RecentLines(recentLogLines),
+	}
+
+	bundle, err := diagnostics.Bundle(info)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build diagnostics bundle: %w", err)
+	}
+	return bundle, nil
+}
+
+// componentStatus reports which sources, enforcers, and optional features
+// this engine has configured, for inclusion in the diagnostics bundle's
+// components.json. It's a configuration snapshot, not a live health check -
+// it doesn't consult Source.Health().
+func (e *Engine) componentStatus() map[string]string {
+	status := make(map[string]string, len(e.sources)+len(e.enforcers)+3)
+	for _, source := range e.sources {
+		status["source:"+source.Name()] = "configured"
+	}
+	for _, enforcer := range e.enforcers {
+		status["enforcer:"+enforcer.Name()] = "configured"
+	}
+	status["storage"] = e.cfg.Storage.Type
+	if e.decisions != nil {
+		status["decisions"] = "enabled"
+	}
+	if e.hub != nil {
+		status["hub"] = "enabled"
+	}
+	return status
+}
+
+// storageDB returns the underlying *sql.DB backing storage, or nil for a
+// backend that isn't SQL-based (e.g. MemoryStorage) or doesn't expose one.
+func storageDB(storage Storage) *sql.DB {
+	switch s := storage.(type) {
+	case *PostgresStorage:
+		return s.db.DB
+	case *SQLiteStorage:
+		return s.db
+	default:
+		return nil
+	}
+}
+
+// redactConfig renders cfg as YAML with secrets masked: the email-abuse
+// SMTP password, the LAPI publish API key, any password embedded in a
+// postgres storage DSN, and any credential-shaped param on a declaratively-
+// configured monitoring source (e.g. a "lapi" source's api_key).
+func redactConfig(cfg *config.Config) (string, error) {
+	redactedCfg := *cfg
+	redactedCfg.JWTSecret = ""
+	redactedCfg.Enforcement.EmailAbuse.SMTP.Password = redacted
+	redactedCfg.Enforcement.LAPIPublish.APIKey = redacted
+	redactedCfg.Storage.DSN = redactDSN(cfg.Storage.DSN)
+	redactedCfg.Monitoring.Sources.Additional = redactSourceParams(cfg.Monitoring.Sources.Additional)
+
+	out, err := yaml.Marshal(&redactedCfg)
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+// sensitiveParamKeySubstrings are case-insensitive substrings that mark a
+// source's Params entry as a credential, so redactSourceParams can mask it
+// without having to know every registered source type's specific param
+// names up front.
+var sensitiveParamKeySubstrings = []string{"key", "secret", "password", "token", "credential"}
+
+// redactSourceParams returns a deep copy of sources with any Params value
+// whose key looks like a credential replaced with redacted. sources' Params
+// maps are copied rather than mutated in place, since cfg is shared with the
+// live engine.
+func redactSourceParams(sources []config.SourceConfig) []config.SourceConfig {
+	redactedSources := make([]config.SourceConfig, len(sources))
+	for i, sc := range sources {
+		redactedSources[i] = sc
+		if sc.Params == nil {
+			continue
+		}
+
+		params := make(map[string]interface{}, len(sc.Params))
+		for key, value := range sc.Params {
+			if isSensitiveParamKey(key) {
+				value = redacted
+			}
+			params[key] = value
+		}
+		redactedSources[i].Params = params
+	}
+	return redactedSources
+}
+
+// isSensitiveParamKey reports whether key looks like it holds a credential.
+func isSensitiveParamKey(key string) bool {
+	lower := strings.ToLower(key)
+	for _, substr := range sensitiveParamKeySubstrings {
+		if strings.Contains(lower, substr) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactDSN masks the password component of a postgres connection URL,
+// leaving a sqlite file path or an already-password-free DSN untouched.
+func redactDSN(dsn string) string {
+	u, err := url.Parse(dsn)
+	if err != nil || u.User == nil {
+		return dsn
+	}
+	if _, hasPassword := u.User.Password(); !hasPassword {
+		return dsn
+	}
+	u.User = url.UserPassword(u.User.Username(), redacted)
+	return u.String()
+}
@@ -0,0 +1,45 @@
+package core
+
+import (
+	"strings"
+	"testing"
+
+	"openbpl/internal/config"
+)
+
+func TestRedactConfigMasksSourceParams(t *testing.T) {
+	cfg := &config.Config{
+		JWTSecret: "super-secret-jwt",
+		Monitoring: config.MonitoringConfig{
+			Sources: config.SourcesConfig{
+				Additional: []config.SourceConfig{
+					{
+						Type:    "lapi",
+						Enabled: true,
+						Params: map[string]interface{}{
+							"url":     "https://lapi.example.com",
+							"api_key": "plaintext-lapi-key",
+						},
+					},
+				},
+			},
+		},
+	}
+
+	out, err := redactConfig(cfg)
+	if err != nil {
+		t.Fatalf("redactConfig() error = %v", err)
+	}
+
+	if strings.Contains(out, "plaintext-lapi-key") {
+		t.Errorf("redacted config leaked source param secret: %s", out)
+	}
+	if !strings.Contains(out, "https://lapi.example.com") {
+		t.Errorf("redacted config dropped non-sensitive source param: %s", out)
+	}
+
+	if len(cfg.Monitoring.Sources.Additional[0].Params) != 2 ||
+		cfg.Monitoring.Sources.Additional[0].Params["api_key"] != "plaintext-lapi-key" {
+		t.Errorf("redactConfig mutated the live config's Params map: %+v", cfg.Monitoring.Sources.Additional[0].Params)
+	}
+}
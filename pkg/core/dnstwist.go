@@ -0,0 +1,168 @@
+// pkg/core/dnstwist.go
+package core
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+)
+
+func init() {
+	RegisterSource("dnstwist", newDNSTwistSourceFromParams)
+}
+
+// DNSTwistSource periodically generates typosquat permutations of a set of
+// keywords (in the style of the dnstwist tool) and emits an event for every
+// permutation that resolves, so downstream detectors/scenarios can evaluate
+// it like any other domain sighting.
+type DNSTwistSource struct {
+	Keywords []string
+	TLDs     []string
+	Interval time.Duration
+	stop     chan struct{}
+}
+
+func newDNSTwistSourceFromParams(params map[string]interface{}) (Source, error) {
+	keywords, err := stringSlice(params, "keywords")
+	if err != nil {
+		return nil, err
+	}
+
+	tlds, err := stringSlice(params, "tlds")
+	if err != nil {
+		return nil, err
+	}
+
+	interval := 1 * time.Hour
+	if raw, ok := params["interval"]; ok {
+		s, ok := raw.(string)
+		if !ok {
+			return nil, fmt.Errorf("param %q must be a string", "interval")
+		}
+		parsed, err := time.ParseDuration(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid interval %q: %w", s, err)
+		}
+		interval = parsed
+	}
+
+	return &DNSTwistSource{Keywords: keywords, TLDs: tlds, Interval: interval}, nil
+}
+
+func (s *DNSTwistSource) Name() string {
+	return "dnstwist"
+}
+
+func (s *DNSTwistSource) Stop() error {
+	if s.stop != nil {
+		close(s.stop)
+	}
+	return nil
+}
+
+// Health always reports healthy: DNSTwistSource polls on a ticker rather
+// than holding a persistent connection, so there's nothing to go stale
+// between ticks.
+func (s *DNSTwistSource) Health() error {
+	return nil
+}
+
+// Start generates permutations for each keyword and checks them for a live
+// DNS resolution on every tick, sending an event to the channel for every
+// permutation that resolves.
+func (s *DNSTwistSource) Start(ctx context.Context, events chan<- Event) error {
+	s.stop = make(chan struct{})
+
+	ticker := time.NewTicker(s.Interval)
+	defer ticker.Stop()
+
+	s.tick(ctx, events)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-s.stop:
+			return nil
+		case <-ticker.C:
+			s.tick(ctx, events)
+		}
+	}
+}
+
+func (s *DNSTwistSource) tick(ctx context.Context, events chan<- Event) {
+	for _, keyword := range s.Keywords {
+		for _, domain := range s.permuteDomain(keyword) {
+			s.checkAndEmit(ctx, keyword, domain, events)
+		}
+	}
+}
+
+func (s *DNSTwistSource) checkAndEmit(ctx context.Context, keyword, domain string, events chan<- Event) {
+	lookupCtx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	addrs, err := net.DefaultResolver.LookupHost(lookupCtx, domain)
+	if err != nil || len(addrs) == 0 {
+		return
+	}
+
+	event := Event{
+		ID:        fmt.Sprintf("dnstwist_%d", time.Now().UnixNano()),
+		Source:    s.Name(),
+		Type:      "permutation_resolved",
+		Domain:    domain,
+		Timestamp: time.Now(),
+		Data: map[string]interface{}{
+			"keyword":   keyword,
+			"addresses": addrs,
+		},
+	}
+
+	select {
+	case events <- event:
+	case <-ctx.Done():
+	}
+}
+
+// permuteDomain generates dnstwist-style permutations of keyword (domain
+// omission, adjacent transposition, and character repetition) across every
+// configured TLD.
+func (s *DNSTwistSource) permuteDomain(keyword string) []string {
+	var names []string
+	seen := make(map[string]bool)
+
+	add := func(name string) {
+		if name == "" || name == keyword || seen[name] {
+			return
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+
+	// Omission: drop one character at a time
+	for i := range keyword {
+		add(keyword[:i] + keyword[i+1:])
+	}
+
+	// Adjacent transposition: swap each pair of neighbouring characters
+	for i := 0; i < len(keyword)-1; i++ {
+		chars := []byte(keyword)
+		chars[i], chars[i+1] = chars[i+1], chars[i]
+		add(string(chars))
+	}
+
+	// Repetition: double each character
+	for i := range keyword {
+		add(keyword[:i+1] + string(keyword[i]) + keyword[i+1:])
+	}
+
+	var out []string
+	for _, tld := range s.TLDs {
+		for _, name := range names {
+			out = append(out, name+"."+tld)
+		}
+	}
+	return out
+}
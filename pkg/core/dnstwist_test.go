@@ -0,0 +1,41 @@
+package core
+
+import "testing"
+
+func TestPermuteDomain(t *testing.T) {
+	s := &DNSTwistSource{TLDs: []string{"com"}}
+
+	perms := s.permuteDomain("paypal")
+
+	if len(perms) == 0 {
+		t.Fatal("expected at least one permutation")
+	}
+
+	seen := make(map[string]bool)
+	for _, p := range perms {
+		if p == "paypal.com" {
+			t.Errorf("permutations must not include the original domain, got %q", p)
+		}
+		if seen[p] {
+			t.Errorf("duplicate permutation: %q", p)
+		}
+		seen[p] = true
+	}
+
+	if !seen["aypal.com"] {
+		t.Errorf("expected omission permutation aypal.com, got %v", perms)
+	}
+	if !seen["apypal.com"] {
+		t.Errorf("expected transposition permutation apypal.com, got %v", perms)
+	}
+	if !seen["ppaypal.com"] {
+		t.Errorf("expected repetition permutation ppaypal.com, got %v", perms)
+	}
+}
+
+func TestPermuteDomainEmptyTLDs(t *testing.T) {
+	s := &DNSTwistSource{}
+	if perms := s.permuteDomain("paypal"); len(perms) != 0 {
+		t.Errorf("expected no permutations without TLDs, got %v", perms)
+	}
+}
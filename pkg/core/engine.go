@@ -3,94 +3,246 @@ package core
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
-	"log"
+	"log/slog"
 	"sync"
 	"time"
 
-	"github.com/openBPL/internal/config"
+	"openbpl/internal/config"
+	"openbpl/internal/handlers/decisions"
+	"openbpl/internal/logging"
+	"openbpl/pkg/core/metrics"
+	"openbpl/pkg/core/scenarios"
+	"openbpl/pkg/core/workers"
+	"openbpl/pkg/hub"
+	"openbpl/pkg/offline"
 )
 
 // Engine is the main monitoring engine
 type Engine struct {
-	cfg       *config.Config
-	sources   []Source
-	enrichers []Enricher
-	detectors []Detector
-	enforcers []Enforcer
-	storage   Storage
-	stats     *Statistics
+	cfg         *config.Config
+	sources     []Source
+	enrichers   []Enricher
+	detectors   []Detector
+	enforcers   []Enforcer
+	storage     Storage
+	stats       *Statistics
+	scenarios   *scenarios.Engine
+	decisions   *decisions.Store
+	decisionTTL time.Duration
+	runner      *Runner
+	pool        *workers.Pool
+	hub         *hub.Hub
+	offline     *offline.Queue
+	build       buildInfo
+	logger      *slog.Logger
+}
+
+// SetOfflineQueue wires q in as the engine's fallback for events and
+// detection results that fail to save because the storage backend is
+// unreachable; they're persisted to q instead of being dropped. Call
+// SyncOffline to replay them once the backend is reachable again. Without a
+// queue there is no offline fallback - saves that fail are only logged.
+func (e *Engine) SetOfflineQueue(q *offline.Queue) {
+	e.offline = q
+}
+
+// Hub returns the engine's Hub client, or nil if the Hub feature isn't
+// enabled. Callers (e.g. the HTTP server or CLI) can use it to manage
+// installed community detector configs without restarting the engine.
+func (e *Engine) Hub() *hub.Hub {
+	return e.hub
+}
+
+// Close releases resources the engine owns, including the storage backend
+// - notably an embedded-postgres backend, whose Close also stops the
+// embedded server. Callers should defer Close right after NewEngine
+// succeeds, so it still runs if Run exits early.
+func (e *Engine) Close() error {
+	return e.storage.Close()
+}
+
+// Decisions returns the engine's decision store, or nil if chunk0-4's
+// decisions feature isn't enabled. Callers (e.g. the HTTP server) can mount
+// internal/handlers/decisions.Handlers on top of it.
+func (e *Engine) Decisions() *decisions.Store {
+	return e.decisions
 }
 
 // Statistics tracks monitoring statistics
 type Statistics struct {
 	mu             sync.RWMutex
-	CertsProcessed int64     `json:"certs_processed"`
-	ThreatsFound   int64     `json:"threats_found"`
-	ActionsLive    int64     `json:"actions_live"`
-	ActionsDryRun  int64     `json:"actions_dry_run"`
-	StartTime      time.Time `json:"start_time"`
+	CertsProcessed int64          `json:"certs_processed"`
+	ThreatsFound   int64          `json:"threats_found"`
+	ActionsLive    int64          `json:"actions_live"`
+	ActionsDryRun  int64          `json:"actions_dry_run"`
+	StartTime      time.Time      `json:"start_time"`
+	QueueDepth     int64          `json:"queue_depth"`
+	InFlight       int64          `json:"in_flight"`
+	HostFailures   map[string]int `json:"host_failures"`
 }
 
 // NewEngine creates a new monitoring engine
 func NewEngine(cfg *config.Config) (*Engine, error) {
-	log.Printf("🔧 Initializing OpenBPL engine...")
+	logger := logging.New(cfg.Logging.Level, cfg.Logging.Format)
+	logger.Info("initializing OpenBPL engine")
 
 	// Initialize storage
-	storage, err := NewStorage(cfg.Storage.Type)
+	storage, err := NewStorage(cfg.Storage)
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize storage: %w", err)
 	}
-	log.Printf("💾 Storage initialized: %s", cfg.Storage.Type)
+	if memStorage, ok := storage.(*MemoryStorage); ok {
+		memStorage.Logger = logger
+	}
+	logger.Info("storage initialized", "type", cfg.Storage.Type)
 
 	// Initialize sources
-	sources, err := initializeSources(cfg)
+	sources, err := initializeSources(cfg, logger)
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize sources: %w", err)
 	}
-	log.Printf("🔌 Sources initialized: %d", len(sources))
+	logger.Info("sources initialized", "count", len(sources))
 
 	// Initialize enrichers
 	enrichers, err := initializeEnrichers(cfg)
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize enrichers: %w", err)
 	}
-	log.Printf("🔍 Enrichers initialized: %d", len(enrichers))
+	logger.Info("enrichers initialized", "count", len(enrichers))
+
+	// Initialize the decisions store, if enabled
+	var decisionStore *decisions.Store
+	var decisionTTL time.Duration
+	if cfg.Decisions.Enabled {
+		decisionStore = decisions.NewStore()
+		decisionTTL = 1 * time.Hour
+		if ttl, err := time.ParseDuration(cfg.Decisions.TTL); err == nil {
+			decisionTTL = ttl
+		}
+		logger.Info("decisions enabled", "ttl", decisionTTL)
+	}
+
+	// Initialize the scenario engine, if enabled
+	var scenarioEngine *scenarios.Engine
+	if cfg.Scenarios.Enabled {
+		scenarioEngine, err = scenarios.NewEngine(cfg.Scenarios.Dir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize scenarios: %w", err)
+		}
+		logger.Info("scenarios loaded", "count", len(scenarioEngine.Scenarios()))
+	}
+
+	// Initialize the hub client, if enabled, so detectors can pull installed
+	// community configs (e.g. favicon reference sets) from its local cache.
+	var hubClient *hub.Hub
+	if cfg.Hub.Enabled {
+		hubClient, err = hub.NewHub(cfg.Hub.CacheDir, cfg.Hub.IndexURL, cfg.Hub.PublicKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize hub: %w", err)
+		}
+		logger.Info("hub cache initialized", "dir", cfg.Hub.CacheDir)
+	}
 
 	// Initialize detectors
-	detectors, err := initializeDetectors(cfg)
+	detectors, err := initializeDetectors(cfg, scenarioEngine, hubClient)
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize detectors: %w", err)
 	}
-	log.Printf("🚨 Detectors initialized: %d", len(detectors))
+	logger.Info("detectors initialized", "count", len(detectors))
 
 	// Initialize enforcers
 	enforcers, err := initializeEnforcers(cfg)
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize enforcers: %w", err)
 	}
-	log.Printf("⚡ Enforcers initialized: %d", len(enforcers))
-
-	return &Engine{
-		cfg:       cfg,
-		sources:   sources,
-		enrichers: enrichers,
-		detectors: detectors,
-		enforcers: enforcers,
-		storage:   storage,
-		stats:     &Statistics{StartTime: time.Now()},
-	}, nil
+	logger.Info("enforcers initialized", "count", len(enforcers))
+
+	engine := &Engine{
+		cfg:         cfg,
+		sources:     sources,
+		enrichers:   enrichers,
+		detectors:   detectors,
+		enforcers:   enforcers,
+		storage:     storage,
+		stats:       &Statistics{StartTime: time.Now()},
+		scenarios:   scenarioEngine,
+		decisions:   decisionStore,
+		decisionTTL: decisionTTL,
+		hub:         hubClient,
+		logger:      logger,
+	}
+	engine.runner = NewRunner()
+	engine.runner.Logger = logger
+	engine.pool = workers.NewPool(cfg.Engine.Workers, storage, engine.decodeQueuedJob)
+	engine.pool.Logger = logger
+	logger.Info("worker pool initialized", "workers", cfg.Engine.Workers)
+
+	return engine, nil
+}
+
+// decodeQueuedJob reconstructs a runnable job from its persisted form. It's
+// the Decoder passed to workers.NewPool, so eventJob/detectionJob never have
+// to be persisted themselves - only their JSON-encoded payloads are.
+func (e *Engine) decodeQueuedJob(qj workers.QueuedJob) (workers.Job, error) {
+	switch qj.Kind {
+	case "event":
+		var event Event
+		if err := json.Unmarshal(qj.Payload, &event); err != nil {
+			return nil, fmt.Errorf("failed to decode event job: %w", err)
+		}
+		return &eventJob{engine: e, event: event}, nil
+	case "detection":
+		var result DetectionResult
+		if err := json.Unmarshal(qj.Payload, &result); err != nil {
+			return nil, fmt.Errorf("failed to decode detection job: %w", err)
+		}
+		return &detectionJob{engine: e, result: result}, nil
+	default:
+		return nil, fmt.Errorf("unknown job kind %q", qj.Kind)
+	}
+}
+
+// eventJob runs the enrichment and detection pipeline for a single event on
+// the worker pool, keyed by domain so a misbehaving host's retries coalesce
+// onto its own backoff/circuit-breaker state instead of blocking others.
+type eventJob struct {
+	engine *Engine
+	event  Event
+}
+
+func (j *eventJob) Key() string { return j.event.Domain }
+
+func (j *eventJob) Run(ctx context.Context) error {
+	return j.engine.runPipeline(logging.WithEventID(ctx, j.event.ID), j.event)
 }
 
+func (j *eventJob) Retryable(err error) bool { return true }
+
+// detectionJob runs the enforcement pipeline for a detection result on the
+// worker pool, so a slow enforcer (e.g. SMTP) can't stall event processing.
+type detectionJob struct {
+	engine *Engine
+	result DetectionResult
+}
+
+func (j *detectionJob) Key() string { return j.result.Domain }
+
+func (j *detectionJob) Run(ctx context.Context) error {
+	j.engine.runEnforcers(logging.WithEventID(ctx, j.result.EventID), j.result)
+	return nil
+}
+
+func (j *detectionJob) Retryable(err error) bool { return true }
+
 // Run starts the monitoring engine
 func (e *Engine) Run(ctx context.Context) error {
-	log.Printf("🚀 Starting OpenBPL monitoring engine...")
-	log.Printf("📊 Mode: %s", func() string {
-		if e.cfg.DryRun {
-			return "DRY-RUN"
-		}
-		return "LIVE"
-	}())
+	mode := "LIVE"
+	if e.cfg.DryRun {
+		mode = "DRY-RUN"
+	}
+	e.logger.Info("starting OpenBPL monitoring engine", "mode", mode)
 
 	// Create event channel for sources to send events
 	events := make(chan Event, 100)
@@ -98,19 +250,26 @@ func (e *Engine) Run(ctx context.Context) error {
 	// Start statistics reporter
 	go e.reportStats(ctx)
 
-	// Start all sources
-	var wg sync.WaitGroup
-	for _, source := range e.sources {
-		wg.Add(1)
-		go func(s Source) {
-			defer wg.Done()
-			log.Printf("🎯 Starting source: %s", s.Name())
-			if err := s.Start(ctx, events); err != nil {
-				log.Printf("❌ Source %s failed: %v", s.Name(), err)
-			}
-		}(source)
+	// Watch source health, logging once on each healthy<->degraded
+	// transition instead of per-reconnect-attempt
+	go e.monitorSourceHealth(ctx)
+
+	// Start the decisions expiry loop, if enabled
+	if e.decisions != nil {
+		go e.decisions.Run(ctx, 30*time.Second)
 	}
 
+	// Start the worker pool that processes queued events and enforcement jobs
+	e.pool.Start(ctx)
+
+	// Start all sources, fanned into the shared event channel
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		e.runner.Run(ctx, e.sources, events)
+	}()
+
 	// Start event processor
 	wg.Add(1)
 	go func() {
@@ -120,7 +279,7 @@ func (e *Engine) Run(ctx context.Context) error {
 
 	// Wait for context cancellation
 	<-ctx.Done()
-	log.Printf("🛑 Monitoring engine stopping...")
+	e.logger.Info("monitoring engine stopping")
 
 	// Close event channel
 	close(events)
@@ -128,11 +287,12 @@ func (e *Engine) Run(ctx context.Context) error {
 	// Wait for all goroutines to finish
 	wg.Wait()
 
-	log.Printf("🛑 Monitoring engine stopped")
+	e.logger.Info("monitoring engine stopped")
 	return nil
 }
 
-// processEvents handles incoming events from sources
+// processEvents hands incoming events off to the worker pool, keyed by
+// domain, instead of running the pipeline inline on this goroutine.
 func (e *Engine) processEvents(ctx context.Context, events <-chan Event) {
 	for {
 		select {
@@ -143,16 +303,28 @@ func (e *Engine) processEvents(ctx context.Context, events <-chan Event) {
 				return // Channel closed
 			}
 
-			// Process the event
-			if err := e.processEvent(ctx, event); err != nil {
-				log.Printf("❌ Failed to process event %s: %v", event.ID, err)
+			if err := e.enqueueEvent(event); err != nil {
+				logging.With(logging.WithEventID(ctx, event.ID), e.logger).Error("failed to enqueue event", "error", err)
 			}
 		}
 	}
 }
 
-// processEvent processes a single event through the pipeline
-func (e *Engine) processEvent(ctx context.Context, event Event) error {
+// enqueueEvent persists event as a queued job so a worker pool goroutine
+// picks it up and runs it through runPipeline.
+func (e *Engine) enqueueEvent(event Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+	return e.pool.EnqueueEvent(event.Domain, payload)
+}
+
+// runPipeline runs a single event through enrichment and detection. It's
+// invoked by eventJob.Run on a worker pool goroutine.
+func (e *Engine) runPipeline(ctx context.Context, event Event) error {
+	log := logging.With(ctx, e.logger)
+
 	// Update statistics
 	e.stats.mu.Lock()
 	e.stats.CertsProcessed++
@@ -160,13 +332,14 @@ func (e *Engine) processEvent(ctx context.Context, event Event) error {
 
 	// Save event to storage
 	if err := e.storage.SaveEvent(event); err != nil {
-		log.Printf("⚠️ Failed to save event: %v", err)
+		log.Warn("failed to save event", "error", err)
+		e.enqueueOffline("event", event.Domain, event)
 	}
 
 	// Run enrichment pipeline
 	for _, enricher := range e.enrichers {
 		if err := enricher.Enrich(ctx, &event); err != nil {
-			log.Printf("⚠️ Enricher %s failed for %s: %v", enricher.Name(), event.Domain, err)
+			log.Warn("enricher failed", "enricher", enricher.Name(), "domain", event.Domain, "error", err)
 			// Continue with other enrichers
 		}
 	}
@@ -176,7 +349,7 @@ func (e *Engine) processEvent(ctx context.Context, event Event) error {
 	for _, detector := range e.detectors {
 		results, err := detector.Detect(ctx, &event)
 		if err != nil {
-			log.Printf("⚠️ Detector %s failed for %s: %v", detector.Name(), event.Domain, err)
+			log.Warn("detector failed", "detector", detector.Name(), "domain", event.Domain, "error", err)
 			continue
 		}
 		allResults = append(allResults, results...)
@@ -185,18 +358,22 @@ func (e *Engine) processEvent(ctx context.Context, event Event) error {
 	// Process detection results
 	for _, result := range allResults {
 		if err := e.processDetectionResult(ctx, result); err != nil {
-			log.Printf("❌ Failed to process detection result: %v", err)
+			log.Error("failed to process detection result", "error", err)
 		}
 	}
 
 	return nil
 }
 
-// processDetectionResult handles a detection result
+// processDetectionResult handles a detection result, handing threats off to
+// the worker pool for enforcement instead of enforcing inline.
 func (e *Engine) processDetectionResult(ctx context.Context, result DetectionResult) error {
+	log := logging.With(ctx, e.logger)
+
 	// Save detection result
 	if err := e.storage.SaveDetection(result); err != nil {
-		log.Printf("⚠️ Failed to save detection result: %v", err)
+		log.Warn("failed to save detection result", "error", err)
+		e.enqueueOffline("detection", result.Domain, result)
 	}
 
 	// Update statistics
@@ -211,13 +388,42 @@ func (e *Engine) processDetectionResult(ctx context.Context, result DetectionRes
 		return nil
 	}
 
-	log.Printf("🚨 THREAT DETECTED: %s (confidence: %.2f, rule: %s)",
-		result.Domain, result.Confidence, result.Rule)
+	log.Warn("threat detected", "domain", result.Domain, "confidence", result.Confidence, "rule", result.Rule)
+	metrics.ThreatsDetected.WithLabelValues(result.Rule).Inc()
+
+	// Publish a bouncer-subscribable decision for this threat
+	if e.decisions != nil {
+		now := time.Now()
+		e.decisions.Add(decisions.Decision{
+			Scope:     "domain",
+			Value:     result.Domain,
+			Type:      "ban",
+			Scenario:  result.Rule,
+			Origin:    "openbpl",
+			CreatedAt: now,
+			ExpiresAt: now.Add(e.decisionTTL),
+		})
+	}
+
+	payload, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("failed to marshal detection result: %w", err)
+	}
+	if err := e.pool.EnqueueDetection(result.Domain, payload); err != nil {
+		return fmt.Errorf("failed to enqueue enforcement job: %w", err)
+	}
+
+	return nil
+}
+
+// runEnforcers runs the enforcement pipeline for a detected threat. It's
+// invoked by detectionJob.Run on a worker pool goroutine.
+func (e *Engine) runEnforcers(ctx context.Context, result DetectionResult) {
+	log := logging.With(ctx, e.logger)
 
-	// Run enforcement pipeline
 	for _, enforcer := range e.enforcers {
 		if err := enforcer.Enforce(ctx, result, e.cfg.DryRun); err != nil {
-			log.Printf("⚠️ Enforcer %s failed for %s: %v", enforcer.Name(), result.Domain, err)
+			log.Warn("enforcer failed", "enforcer", enforcer.Name(), "domain", result.Domain, "error", err)
 			continue
 		}
 
@@ -230,7 +436,119 @@ func (e *Engine) processDetectionResult(ctx context.Context, result DetectionRes
 		}
 		e.stats.mu.Unlock()
 	}
+}
 
+// enqueueOffline persists payload to the offline fallback queue, if one is
+// configured via SetOfflineQueue, under kind/key, so it can be replayed by
+// SyncOffline once the storage backend is reachable again. Marshal/enqueue
+// failures are only logged, since the caller already logged the save
+// failure this is recovering from.
+func (e *Engine) enqueueOffline(kind, key string, payload interface{}) {
+	if e.offline == nil {
+		return
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		e.logger.Error("failed to marshal payload for offline queue", "kind", kind, "error", err)
+		return
+	}
+	if err := e.offline.Enqueue(offline.Action{Kind: kind, Key: key, Payload: data}); err != nil {
+		e.logger.Error("failed to enqueue to offline queue", "kind", kind, "error", err)
+	}
+}
+
+// SyncOffline replays up to n actions from the offline queue against
+// storage, oldest first. It stops at the first action that fails and
+// re-enqueues it and the rest of the batch, in their original order, at the
+// back of the queue (behind anything still queued), returning how many were
+// replayed successfully and that action's error. Returns (0, nil) if no
+// offline queue is configured.
+func (e *Engine) SyncOffline(n int) (int, error) {
+	if e.offline == nil {
+		return 0, nil
+	}
+
+	actions, err := e.offline.Dequeue(n)
+	if err != nil {
+		return 0, fmt.Errorf("failed to dequeue offline actions: %w", err)
+	}
+
+	for i, action := range actions {
+		if err := e.replayOfflineAction(action); err != nil {
+			for _, remaining := range actions[i:] {
+				if reErr := e.offline.Enqueue(remaining); reErr != nil {
+					e.logger.Error("failed to re-enqueue offline action", "action_id", remaining.ID, "error", reErr)
+				}
+			}
+			return i, err
+		}
+	}
+	return len(actions), nil
+}
+
+// replayOfflineAction re-saves a single action dequeued from the offline
+// queue, dispatching on its Kind to the storage call it originally failed.
+func (e *Engine) replayOfflineAction(action offline.Action) error {
+	switch action.Kind {
+	case "event":
+		var event Event
+		if err := json.Unmarshal(action.Payload, &event); err != nil {
+			return fmt.Errorf("failed to unmarshal offline event: %w", err)
+		}
+		return e.storage.SaveEvent(event)
+	case "detection":
+		var result DetectionResult
+		if err := json.Unmarshal(action.Payload, &result); err != nil {
+			return fmt.Errorf("failed to unmarshal offline detection: %w", err)
+		}
+		return e.storage.SaveDetection(result)
+	default:
+		return fmt.Errorf("unknown offline action kind %q", action.Kind)
+	}
+}
+
+// monitorSourceHealth polls every source's Health() and logs once on each
+// healthy<->degraded transition, rather than letting a reconnecting source
+// spam a WARN line per attempt. A degraded source is never fatal - it's
+// just surfaced here for an operator to notice.
+func (e *Engine) monitorSourceHealth(ctx context.Context) {
+	ticker := time.NewTicker(30 * time.Second)
+	defer ticker.Stop()
+
+	degraded := make(map[string]bool, len(e.sources))
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for _, source := range e.sources {
+				err := source.Health()
+				switch {
+				case err != nil && !degraded[source.Name()]:
+					e.logger.Warn("source is degraded", "source", source.Name(), "error", err)
+					degraded[source.Name()] = true
+				case err == nil && degraded[source.Name()]:
+					e.logger.Info("source recovered", "source", source.Name())
+					degraded[source.Name()] = false
+				}
+			}
+		}
+	}
+}
+
+// HealthCheck reports whether the engine is fit to keep running: nil if
+// every source is healthy, or the first source's Health() error otherwise.
+// It's meant for a caller that needs a single pass/fail signal - e.g. a
+// systemd watchdog ping - rather than monitorSourceHealth's per-source
+// transition logging.
+func (e *Engine) HealthCheck() error {
+	for _, source := range e.sources {
+		if err := source.Health(); err != nil {
+			return fmt.Errorf("source %s is unhealthy: %w", source.Name(), err)
+		}
+	}
 	return nil
 }
 
@@ -244,22 +562,29 @@ func (e *Engine) reportStats(ctx context.Context) {
 		case <-ctx.Done():
 			return
 		case <-ticker.C:
-			e.stats.mu.RLock()
+			poolStats := e.pool.Stats()
+
+			e.stats.mu.Lock()
+			e.stats.QueueDepth = poolStats.QueueDepth
+			e.stats.InFlight = poolStats.InFlight
+			e.stats.HostFailures = poolStats.HostFailures
 			uptime := time.Since(e.stats.StartTime)
-			log.Printf("📊 Stats - Uptime: %v, Certs: %d, Threats: %d, Actions: %d (live) + %d (dry-run)",
-				uptime.Round(time.Second),
-				e.stats.CertsProcessed,
-				e.stats.ThreatsFound,
-				e.stats.ActionsLive,
-				e.stats.ActionsDryRun,
+			e.logger.Info("stats",
+				"uptime", uptime.Round(time.Second),
+				"certs", e.stats.CertsProcessed,
+				"threats", e.stats.ThreatsFound,
+				"actions_live", e.stats.ActionsLive,
+				"actions_dry_run", e.stats.ActionsDryRun,
+				"queue_depth", e.stats.QueueDepth,
+				"in_flight", e.stats.InFlight,
 			)
-			e.stats.mu.RUnlock()
+			e.stats.mu.Unlock()
 		}
 	}
 }
 
 // Helper functions to initialize components
-func initializeSources(cfg *config.Config) ([]Source, error) {
+func initializeSources(cfg *config.Config, logger *slog.Logger) ([]Source, error) {
 	var sources []Source
 
 	// Initialize certstream source if enabled
@@ -267,6 +592,23 @@ func initializeSources(cfg *config.Config) ([]Source, error) {
 		source := &CertstreamSource{
 			URL:      cfg.Monitoring.Sources.Certstream.URL,
 			Keywords: cfg.Monitoring.Sources.Certstream.Keywords,
+			Logger:   logger,
+		}
+		sources = append(sources, source)
+	}
+
+	// Initialize any declaratively-configured sources (dnstwist, http_feed,
+	// etc.) registered via RegisterSource.
+	for _, sc := range cfg.Monitoring.Sources.Additional {
+		if !sc.Enabled {
+			continue
+		}
+		source, err := NewRegisteredSource(sc.Type, sc.Params)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize source %q: %w", sc.Type, err)
+		}
+		if settable, ok := source.(interface{ SetLogger(*slog.Logger) }); ok {
+			settable.SetLogger(logger)
 		}
 		sources = append(sources, source)
 	}
@@ -297,18 +639,43 @@ func initializeEnrichers(cfg *config.Config) ([]Enricher, error) {
 	return enrichers, nil
 }
 
-func initializeDetectors(cfg *config.Config) ([]Detector, error) {
+func initializeDetectors(cfg *config.Config, scenarioEngine *scenarios.Engine, hubClient *hub.Hub) ([]Detector, error) {
 	var detectors []Detector
 
 	// Initialize favicon similarity detector if enabled
 	if cfg.Rules.FaviconSimilarity.Enabled {
+		referenceFavicons := cfg.Rules.FaviconSimilarity.ReferenceFavicons
+
+		// Layer in any favicon sets installed from the hub, so operators can
+		// pull reference favicons from the community index instead of (or in
+		// addition to) listing them in config.yaml.
+		if hubClient != nil {
+			hubFavicons, err := hubClient.ReferenceFavicons()
+			if err != nil {
+				return nil, fmt.Errorf("failed to load hub reference favicons: %w", err)
+			}
+			merged := make(map[string]string, len(referenceFavicons)+len(hubFavicons))
+			for brand, url := range hubFavicons {
+				merged[brand] = url
+			}
+			for brand, url := range referenceFavicons {
+				merged[brand] = url
+			}
+			referenceFavicons = merged
+		}
+
 		detector := &FaviconSimilarityDetector{
 			Threshold:         cfg.Rules.FaviconSimilarity.Threshold,
-			ReferenceFavicons: cfg.Rules.FaviconSimilarity.ReferenceFavicons,
+			ReferenceFavicons: referenceFavicons,
 		}
 		detectors = append(detectors, detector)
 	}
 
+	// Initialize the scenario detector if enabled
+	if scenarioEngine != nil {
+		detectors = append(detectors, &ScenarioDetector{Engine: scenarioEngine})
+	}
+
 	return detectors, nil
 }
 
@@ -330,5 +697,21 @@ func initializeEnforcers(cfg *config.Config) ([]Enforcer, error) {
 		enforcers = append(enforcers, enforcer)
 	}
 
+	// Add the LAPI publisher if enabled, pushing confirmed threats to a
+	// peer OpenBPL instance's LAPI instead of (or alongside) acting on them
+	// locally.
+	if cfg.Enforcement.LAPIPublish.Enabled {
+		ttl, err := time.ParseDuration(cfg.Enforcement.LAPIPublish.TTL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid lapi_publish ttl %q: %w", cfg.Enforcement.LAPIPublish.TTL, err)
+		}
+		enforcer := &LAPIPublisher{
+			URL:    cfg.Enforcement.LAPIPublish.URL,
+			APIKey: cfg.Enforcement.LAPIPublish.APIKey,
+			TTL:    ttl,
+		}
+		enforcers = append(enforcers, enforcer)
+	}
+
 	return enforcers, nil
 }
@@ -0,0 +1,23 @@
+package core
+
+import "testing"
+
+func TestEngineHealthCheckPassesWhenAllSourcesHealthy(t *testing.T) {
+	e := &Engine{sources: []Source{&CertstreamSource{}, &CertstreamSource{}}}
+
+	if err := e.HealthCheck(); err != nil {
+		t.Errorf("expected no error with all sources healthy, got %v", err)
+	}
+}
+
+func TestEngineHealthCheckFailsWhenAnySourceUnhealthy(t *testing.T) {
+	healthy := &CertstreamSource{}
+	unhealthy := &CertstreamSource{}
+	unhealthy.setHealth(errFakeConnect)
+
+	e := &Engine{sources: []Source{healthy, unhealthy}}
+
+	if err := e.HealthCheck(); err == nil {
+		t.Error("expected an error when a source is unhealthy")
+	}
+}
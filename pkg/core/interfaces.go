@@ -3,9 +3,15 @@ package core
 
 import (
 	"context"
+	"log/slog"
+	"sync"
 	"time"
 
-	"github.com/openBPL/internal/config"
+	"github.com/gorilla/websocket"
+
+	"openbpl/internal/config"
+	"openbpl/pkg/core/metrics"
+	"openbpl/pkg/core/workers"
 )
 
 // Source represents a data source for monitoring
@@ -18,6 +24,12 @@ type Source interface {
 
 	// Stop gracefully stops the source
 	Stop() error
+
+	// Health reports nil if the source is operating normally, or an error
+	// describing why it isn't - e.g. a streaming source stuck reconnecting.
+	// Engine.Run polls this to mark a source degraded without taking the
+	// whole pipeline down.
+	Health() error
 }
 
 // Enricher adds additional data to events
@@ -61,6 +73,14 @@ type Storage interface {
 	// GetDetections retrieves detection results with optional filters
 	GetDetections(filters map[string]interface{}) ([]DetectionResult, error)
 
+	// EnqueueJob persists a job onto the durable work queue backing
+	// pkg/core/workers.Pool, so it survives a crash/restart.
+	EnqueueJob(job workers.QueuedJob) error
+
+	// DequeueBatch pops up to n jobs whose NextAttempt is due, removing
+	// them from the queue.
+	DequeueBatch(n int) ([]workers.QueuedJob, error)
+
 	// Close closes the storage connection
 	Close() error
 }
@@ -95,17 +115,103 @@ type DetectionResult struct {
 type CertstreamSource struct {
 	URL      string
 	Keywords []string
+	// Logger receives structured logs for this source. If nil, slog.Default()
+	// is used.
+	Logger *slog.Logger
+
+	// conn, stopCh, and the health/event-age fields below are owned by
+	// Start/connect; guarded by mu since Stop and Health can be called
+	// from another goroutine while Start is running.
+	mu          sync.Mutex
+	conn        *websocket.Conn
+	stopCh      chan struct{}
+	unhealthy   error
+	lastEventAt time.Time
+}
+
+// logger returns s.Logger, falling back to slog.Default() when unset.
+func (s *CertstreamSource) logger() *slog.Logger {
+	if s.Logger != nil {
+		return s.Logger
+	}
+	return slog.Default()
 }
 
 func (s *CertstreamSource) Name() string {
 	return "certstream"
 }
 
+// Stop signals Start's reconnect loop to exit and, if a read is currently
+// blocked, forces it to return immediately by expiring the connection's
+// read deadline rather than waiting for it to elapse naturally.
 func (s *CertstreamSource) Stop() error {
-	// TODO: Implement cleanup
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.stopCh != nil {
+		close(s.stopCh)
+		s.stopCh = nil
+	}
+	if s.conn != nil {
+		s.conn.SetReadDeadline(time.Unix(0, 0))
+	}
 	return nil
 }
 
+// Health reports the error from the most recent connect/read attempt once
+// certstreamDegradeAfter consecutive attempts have failed, or nil once a
+// connection attempt has succeeded since.
+func (s *CertstreamSource) Health() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.unhealthy
+}
+
+// SetReadDeadline sets the read deadline on the active websocket
+// connection, if any, same as (*websocket.Conn).SetReadDeadline. It's a
+// no-op before the first successful connect.
+func (s *CertstreamSource) SetReadDeadline(t time.Time) error {
+	s.mu.Lock()
+	conn := s.conn
+	s.mu.Unlock()
+	if conn == nil {
+		return nil
+	}
+	return conn.SetReadDeadline(t)
+}
+
+// SetWriteDeadline sets the write deadline on the active websocket
+// connection, if any, same as (*websocket.Conn).SetWriteDeadline. It's a
+// no-op before the first successful connect.
+func (s *CertstreamSource) SetWriteDeadline(t time.Time) error {
+	s.mu.Lock()
+	conn := s.conn
+	s.mu.Unlock()
+	if conn == nil {
+		return nil
+	}
+	return conn.SetWriteDeadline(t)
+}
+
+func (s *CertstreamSource) setHealth(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.unhealthy = err
+}
+
+func (s *CertstreamSource) setConn(conn *websocket.Conn) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.conn = conn
+}
+
+func (s *CertstreamSource) touchLastEvent() {
+	s.mu.Lock()
+	s.lastEventAt = time.Now()
+	s.mu.Unlock()
+	metrics.SourceLastEventAge.WithLabelValues(s.Name()).Set(0)
+}
+
 // HTMLEnricher fetches HTML content for domains
 type HTMLEnricher struct {
 	Timeout   string
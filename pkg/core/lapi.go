@@ -0,0 +1,221 @@
+// pkg/core/lapi.go
+package core
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"openbpl/internal/handlers/decisions"
+	"openbpl/pkg/core/metrics"
+)
+
+func init() {
+	RegisterSource("lapi", newLAPISourceFromParams)
+}
+
+// lapiRetryInterval bounds how quickly LAPISource retries a peer it failed
+// to reach, so a down LAPI doesn't turn into a tight request loop.
+const lapiRetryInterval = 5 * time.Second
+
+// lapiDegradeAfter is how many consecutive poll failures it takes before
+// Health() reports the source unhealthy.
+const lapiDegradeAfter = 5
+
+// LAPISource long-polls a peer OpenBPL instance's decisions stream (GET
+// /api/v1/decisions/stream) and re-emits every decision it publishes as a
+// local Event - the agent half of the LAPI split described in chunk0-4,
+// mirroring the role crowdsec-agent plays against a central CrowdSec LAPI.
+type LAPISource struct {
+	URL    string
+	APIKey string
+	// Logger receives structured logs for this source. If nil, slog.Default()
+	// is used.
+	Logger *slog.Logger
+
+	client *http.Client
+	cursor int64
+	stop   chan struct{}
+
+	mu        sync.Mutex
+	unhealthy error
+}
+
+// logger returns s.Logger, falling back to slog.Default() when unset.
+func (s *LAPISource) logger() *slog.Logger {
+	if s.Logger != nil {
+		return s.Logger
+	}
+	return slog.Default()
+}
+
+// SetLogger sets the logger this source uses, so initializeSources can wire
+// in the engine's shared logger for sources built declaratively through the
+// SourceFactory registry rather than a struct literal.
+func (s *LAPISource) SetLogger(logger *slog.Logger) {
+	s.Logger = logger
+}
+
+func newLAPISourceFromParams(params map[string]interface{}) (Source, error) {
+	rawURL, _ := params["url"].(string)
+	if rawURL == "" {
+		return nil, fmt.Errorf("missing required param %q", "url")
+	}
+
+	apiKey, _ := params["api_key"].(string)
+	if apiKey == "" {
+		return nil, fmt.Errorf("missing required param %q", "api_key")
+	}
+
+	return &LAPISource{URL: rawURL, APIKey: apiKey}, nil
+}
+
+func (s *LAPISource) Name() string {
+	return "lapi"
+}
+
+func (s *LAPISource) Stop() error {
+	if s.stop != nil {
+		close(s.stop)
+	}
+	return nil
+}
+
+// Health reports the error from the most recent poll once lapiDegradeAfter
+// consecutive attempts have failed, and nil once a poll has succeeded
+// since.
+func (s *LAPISource) Health() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.unhealthy
+}
+
+func (s *LAPISource) setHealth(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.unhealthy = err
+}
+
+// Start long-polls the peer's decisions stream until ctx is done, emitting
+// an Event for every newly published decision. The first poll replays the
+// peer's retained change log (startup=true); every poll after that resumes from
+// the cursor the peer returned.
+func (s *LAPISource) Start(ctx context.Context, events chan<- Event) error {
+	s.stop = make(chan struct{})
+	s.client = &http.Client{Timeout: 45 * time.Second}
+
+	startup := true
+	consecutiveFailures := 0
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-s.stop:
+			return nil
+		default:
+		}
+
+		cursor, err := s.poll(ctx, startup, events)
+		if err != nil {
+			consecutiveFailures++
+			metrics.SourceReconnects.WithLabelValues(s.Name()).Inc()
+			if consecutiveFailures >= lapiDegradeAfter {
+				s.setHealth(fmt.Errorf("%d consecutive poll failures: %w", consecutiveFailures, err))
+			}
+			s.logger().Error("LAPI source poll failed", "url", s.URL, "error", err)
+			select {
+			case <-time.After(lapiRetryInterval):
+			case <-ctx.Done():
+				return nil
+			case <-s.stop:
+				return nil
+			}
+			continue
+		}
+
+		consecutiveFailures = 0
+		s.setHealth(nil)
+		s.cursor = cursor
+		startup = false
+	}
+}
+
+// poll issues a single GET against the peer's stream endpoint, emitting an
+// Event for every active decision it returns, and reports the cursor to
+// resume from on the next call.
+func (s *LAPISource) poll(ctx context.Context, startup bool, events chan<- Event) (int64, error) {
+	endpoint := strings.TrimSuffix(s.URL, "/") + "/api/v1/decisions/stream"
+
+	q := url.Values{}
+	if startup {
+		q.Set("startup", "true")
+	} else {
+		q.Set("since", strconv.FormatInt(s.cursor, 10))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint+"?"+q.Encode(), nil)
+	if err != nil {
+		return s.cursor, fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+s.APIKey)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return s.cursor, fmt.Errorf("failed to reach peer LAPI: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return s.cursor, fmt.Errorf("peer LAPI returned status %d", resp.StatusCode)
+	}
+
+	cursor := s.cursor
+	if raw := resp.Header.Get("X-Decision-Cursor"); raw != "" {
+		if parsed, err := strconv.ParseInt(raw, 10, 64); err == nil {
+			cursor = parsed
+		}
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		var event decisions.Event
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			continue
+		}
+		if event.Deleted {
+			continue
+		}
+		s.emit(ctx, event.Decision, events)
+	}
+
+	return cursor, scanner.Err()
+}
+
+func (s *LAPISource) emit(ctx context.Context, d decisions.Decision, events chan<- Event) {
+	event := Event{
+		ID:        fmt.Sprintf("lapi_%s", d.ID),
+		Source:    s.Name(),
+		Type:      "lapi_decision",
+		Domain:    d.Value,
+		Timestamp: time.Now(),
+		Data: map[string]interface{}{
+			"decision_type": d.Type,
+			"scenario":      d.Scenario,
+			"origin":        d.Origin,
+			"expires_at":    d.ExpiresAt,
+		},
+	}
+
+	select {
+	case events <- event:
+	case <-ctx.Done():
+	}
+}
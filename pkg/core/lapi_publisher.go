@@ -0,0 +1,84 @@
+// pkg/core/lapi_publisher.go
+package core
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// LAPIPublisher is an Enforcer that POSTs a confirmed threat to a peer
+// OpenBPL instance's LAPI (POST /api/v1/decisions) instead of - or
+// alongside - acting on it locally, so this agent's detections feed the
+// shared block/allow list other agents pull via LAPISource.
+type LAPIPublisher struct {
+	URL    string
+	APIKey string
+	TTL    time.Duration
+
+	// Client is the HTTP client used to reach the peer. If nil,
+	// http.DefaultClient is used.
+	Client *http.Client
+}
+
+func (e *LAPIPublisher) Name() string {
+	return "lapi_publisher"
+}
+
+// Enforce publishes result as a decision on the peer LAPI. Like the other
+// enforcers, it's skipped entirely in dry-run mode rather than publishing a
+// decision that wouldn't actually be enforced anywhere.
+func (e *LAPIPublisher) Enforce(ctx context.Context, result DetectionResult, dryRun bool) error {
+	if dryRun {
+		return nil
+	}
+
+	payload, err := json.Marshal(map[string]interface{}{
+		"scope":    "domain",
+		"value":    result.Domain,
+		"type":     "ban",
+		"scenario": result.Rule,
+		"ttl":      e.ttl().String(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal decision: %w", err)
+	}
+
+	endpoint := strings.TrimSuffix(e.URL, "/") + "/api/v1/decisions"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+e.APIKey)
+
+	resp, err := e.client().Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach peer LAPI: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return fmt.Errorf("peer LAPI returned status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+func (e *LAPIPublisher) ttl() time.Duration {
+	if e.TTL == 0 {
+		return 1 * time.Hour
+	}
+	return e.TTL
+}
+
+func (e *LAPIPublisher) client() *http.Client {
+	if e.Client != nil {
+		return e.Client
+	}
+	return http.DefaultClient
+}
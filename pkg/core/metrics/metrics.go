@@ -0,0 +1,89 @@
+// Package metrics holds the Prometheus instruments for the monitoring
+// pipeline (as opposed to the HTTP-facing instruments in
+// internal/middleware, which track the API server itself).
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	// CertstreamMessagesReceived counts raw messages read off the
+	// certstream websocket, before parsing.
+	CertstreamMessagesReceived = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "openbpl_certstream_messages_received_total",
+		Help: "Total number of messages received from certstream.",
+	})
+
+	// CertstreamEntriesParsed counts certificate_update entries
+	// successfully unmarshalled from a certstream message.
+	CertstreamEntriesParsed = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "openbpl_certstream_entries_parsed_total",
+		Help: "Total number of certificate entries successfully parsed from certstream.",
+	})
+
+	// DomainsExtracted counts domains pulled out of parsed certificate
+	// entries (CN + SANs), before keyword filtering.
+	DomainsExtracted = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "openbpl_domains_extracted_total",
+		Help: "Total number of domains extracted from certificate entries.",
+	})
+
+	// ScenarioMatches counts detections by the scenario/rule that fired.
+	ScenarioMatches = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "openbpl_scenario_matches_total",
+		Help: "Total number of scenario matches, labeled by scenario name.",
+	}, []string{"scenario"})
+
+	// EventChannelDrops counts events dropped because a source's output
+	// channel was full, labeled by source name.
+	EventChannelDrops = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "openbpl_event_channel_drops_total",
+		Help: "Total number of events dropped due to a full event channel, labeled by source.",
+	}, []string{"source"})
+
+	// StorageSaveDuration tracks how long Storage save calls take,
+	// labeled by backend (memory, sqlite, postgres) and operation
+	// (save_event, save_detection).
+	StorageSaveDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "openbpl_storage_save_duration_seconds",
+		Help:    "Duration of Storage save calls in seconds.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"backend", "operation"})
+
+	// ThreatsDetected counts confirmed brand-infringement detections
+	// (DetectionResult.IsThreat), labeled by the rule that fired.
+	ThreatsDetected = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "openbpl_threats_detected_total",
+		Help: "Total number of confirmed brand-infringement detections, labeled by rule.",
+	}, []string{"rule"})
+
+	// SourceReconnects counts how many times a streaming Source (e.g.
+	// CertstreamSource) has had to reconnect, labeled by source name.
+	SourceReconnects = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "openbpl_sources_reconnects_total",
+		Help: "Total number of reconnect attempts by a streaming source, labeled by source.",
+	}, []string{"source"})
+
+	// SourceLastEventAge reports how long it's been since a source last
+	// delivered an event, labeled by source name - a stuck-but-connected
+	// source shows up here before Source.Health() would call it unhealthy.
+	SourceLastEventAge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "openbpl_source_last_event_age_seconds",
+		Help: "Seconds since a source last delivered an event, labeled by source.",
+	}, []string{"source"})
+)
+
+func init() {
+	prometheus.MustRegister(
+		CertstreamMessagesReceived,
+		CertstreamEntriesParsed,
+		DomainsExtracted,
+		ScenarioMatches,
+		EventChannelDrops,
+		StorageSaveDuration,
+		ThreatsDetected,
+		SourceReconnects,
+		SourceLastEventAge,
+	)
+}
@@ -0,0 +1,67 @@
+// pkg/core/registry.go
+package core
+
+import (
+	"fmt"
+	"sync"
+)
+
+// SourceFactory builds a Source from its declarative YAML params (see
+// config.SourceConfig). Implementations typically register themselves from
+// an init() function in the file that defines the Source.
+type SourceFactory func(params map[string]interface{}) (Source, error)
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]SourceFactory)
+)
+
+// RegisterSource registers a Source factory under name so it can be
+// instantiated declaratively from config.Monitoring.Sources.Additional.
+func RegisterSource(name string, factory SourceFactory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry[name] = factory
+}
+
+// NewRegisteredSource builds the Source registered under name using params.
+func NewRegisteredSource(name string, params map[string]interface{}) (Source, error) {
+	registryMu.RLock()
+	factory, ok := registry[name]
+	registryMu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("unknown source type: %s", name)
+	}
+
+	return factory(params)
+}
+
+// stringSlice extracts a []string param, accepting either a native
+// []string or the []interface{} that YAML/JSON decoding normally produces.
+func stringSlice(params map[string]interface{}, key string) ([]string, error) {
+	raw, ok := params[key]
+	if !ok {
+		return nil, fmt.Errorf("missing required param %q", key)
+	}
+
+	if s, ok := raw.([]string); ok {
+		return s, nil
+	}
+
+	list, ok := raw.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("param %q must be a list of strings", key)
+	}
+
+	out := make([]string, 0, len(list))
+	for _, item := range list {
+		s, ok := item.(string)
+		if !ok {
+			return nil, fmt.Errorf("param %q must be a list of strings", key)
+		}
+		out = append(out, s)
+	}
+
+	return out, nil
+}
@@ -0,0 +1,60 @@
+package core
+
+import "testing"
+
+func TestRegisterAndNewRegisteredSource(t *testing.T) {
+	RegisterSource("test_registry_source", func(params map[string]interface{}) (Source, error) {
+		return &CertstreamSource{URL: params["url"].(string)}, nil
+	})
+
+	source, err := NewRegisteredSource("test_registry_source", map[string]interface{}{"url": "wss://example.com"})
+	if err != nil {
+		t.Fatalf("NewRegisteredSource returned error: %v", err)
+	}
+	if source.Name() != "certstream" {
+		t.Errorf("expected underlying certstream source, got name %q", source.Name())
+	}
+}
+
+func TestNewRegisteredSourceUnknownType(t *testing.T) {
+	if _, err := NewRegisteredSource("does-not-exist", nil); err == nil {
+		t.Fatal("expected an error for an unregistered source type")
+	}
+}
+
+func TestStringSlice(t *testing.T) {
+	t.Run("native slice", func(t *testing.T) {
+		params := map[string]interface{}{"keywords": []string{"paypal", "amazon"}}
+		got, err := stringSlice(params, "keywords")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(got) != 2 || got[0] != "paypal" {
+			t.Errorf("unexpected result: %v", got)
+		}
+	})
+
+	t.Run("interface slice", func(t *testing.T) {
+		params := map[string]interface{}{"keywords": []interface{}{"paypal", "amazon"}}
+		got, err := stringSlice(params, "keywords")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(got) != 2 || got[1] != "amazon" {
+			t.Errorf("unexpected result: %v", got)
+		}
+	})
+
+	t.Run("missing key", func(t *testing.T) {
+		if _, err := stringSlice(map[string]interface{}{}, "keywords"); err == nil {
+			t.Fatal("expected an error for a missing key")
+		}
+	})
+
+	t.Run("wrong type", func(t *testing.T) {
+		params := map[string]interface{}{"keywords": []interface{}{1, 2}}
+		if _, err := stringSlice(params, "keywords"); err == nil {
+			t.Fatal("expected an error for non-string list entries")
+		}
+	})
+}
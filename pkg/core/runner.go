@@ -0,0 +1,118 @@
+// pkg/core/runner.go
+package core
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+)
+
+// SourceStats tracks how many events a source emitted versus dropped due to
+// backpressure on the shared event channel.
+type SourceStats struct {
+	Emitted int64
+	Dropped int64
+}
+
+// Runner fans multiple Sources into one shared event channel. Each source
+// gets its own small buffer so a slow or stuck source can't block the
+// others, and per-source emitted/dropped counters are kept so operators can
+// see which source is overwhelming the pipeline.
+type Runner struct {
+	// Logger receives structured logs for the fan-in loop. If nil,
+	// slog.Default() is used.
+	Logger *slog.Logger
+
+	mu    sync.Mutex
+	stats map[string]*SourceStats
+}
+
+// NewRunner creates an empty Runner.
+func NewRunner() *Runner {
+	return &Runner{stats: make(map[string]*SourceStats)}
+}
+
+// logger returns r.Logger, falling back to slog.Default() when unset.
+func (r *Runner) logger() *slog.Logger {
+	if r.Logger != nil {
+		return r.Logger
+	}
+	return slog.Default()
+}
+
+// Stats returns a snapshot of the per-source emitted/dropped counters.
+func (r *Runner) Stats() map[string]SourceStats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	out := make(map[string]SourceStats, len(r.stats))
+	for name, s := range r.stats {
+		out[name] = SourceStats{
+			Emitted: atomic.LoadInt64(&s.Emitted),
+			Dropped: atomic.LoadInt64(&s.Dropped),
+		}
+	}
+	return out
+}
+
+func (r *Runner) statsFor(name string) *SourceStats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	s, ok := r.stats[name]
+	if !ok {
+		s = &SourceStats{}
+		r.stats[name] = s
+	}
+	return s
+}
+
+// Run starts every source, each fed through its own small buffered
+// channel, and fans their events into out. It blocks until ctx is done and
+// every source's Start call has returned.
+func (r *Runner) Run(ctx context.Context, sources []Source, out chan<- Event) {
+	var wg sync.WaitGroup
+
+	for _, source := range sources {
+		wg.Add(1)
+		go func(s Source) {
+			defer wg.Done()
+			r.runSource(ctx, s, out)
+		}(source)
+	}
+
+	wg.Wait()
+}
+
+func (r *Runner) runSource(ctx context.Context, s Source, out chan<- Event) {
+	stats := r.statsFor(s.Name())
+	sourceEvents := make(chan Event, 16)
+
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event, ok := <-sourceEvents:
+				if !ok {
+					return
+				}
+				select {
+				case out <- event:
+					atomic.AddInt64(&stats.Emitted, 1)
+				case <-ctx.Done():
+					return
+				default:
+					atomic.AddInt64(&stats.Dropped, 1)
+					r.logger().Warn("dropping event, shared channel full", "source", s.Name())
+				}
+			}
+		}
+	}()
+
+	r.logger().Info("starting source", "source", s.Name())
+	if err := s.Start(ctx, sourceEvents); err != nil {
+		r.logger().Error("source failed", "source", s.Name(), "error", err)
+	}
+}
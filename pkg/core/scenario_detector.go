@@ -0,0 +1,69 @@
+// pkg/core/scenario_detector.go
+package core
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"openbpl/pkg/core/metrics"
+	"openbpl/pkg/core/scenarios"
+)
+
+// ScenarioDetector adapts a scenarios.Engine to the Detector interface,
+// evaluating every loaded scenario against the event's domain and emitting
+// a DetectionResult per match whose action is "store" or "emit". Matches
+// whose action is "log" are logged but not turned into a DetectionResult.
+type ScenarioDetector struct {
+	Engine *scenarios.Engine
+}
+
+func (d *ScenarioDetector) Name() string {
+	return "scenarios"
+}
+
+func (d *ScenarioDetector) Detect(ctx context.Context, event *Event) ([]DetectionResult, error) {
+	matches := d.Engine.Evaluate(event.Domain)
+
+	results := make([]DetectionResult, 0, len(matches))
+	for _, match := range matches {
+		metrics.ScenarioMatches.WithLabelValues(match.Scenario.Name).Inc()
+
+		if match.Scenario.Action == "log" {
+			log.Printf("📋 Scenario %q matched %s (keywords: %v, patterns: %v)",
+				match.Scenario.Name, match.Domain, match.MatchedKeywords, match.MatchedPatterns)
+			continue
+		}
+
+		results = append(results, DetectionResult{
+			EventID:    event.ID,
+			Domain:     match.Domain,
+			IsThreat:   true,
+			Confidence: 1.0,
+			Brand:      match.Scenario.Brand,
+			Rule:       match.Scenario.Name,
+			DetectedAt: time.Now(),
+			Metadata: map[string]interface{}{
+				"matched_keywords":   match.MatchedKeywords,
+				"matched_patterns":   match.MatchedPatterns,
+				"similarity_matched": match.SimilarityMatched,
+				"skeleton":           match.Skeleton,
+				"distance":           match.Distance,
+				"severity":           match.Scenario.Severity,
+				"action":             match.Scenario.Action,
+			},
+		})
+	}
+
+	return results, nil
+}
+
+// ReloadScenarios re-reads the engine's scenario directory without
+// restarting the process.
+func (e *Engine) ReloadScenarios() error {
+	if e.scenarios == nil {
+		return fmt.Errorf("scenario engine is not enabled")
+	}
+	return e.scenarios.Reload()
+}
@@ -0,0 +1,170 @@
+// pkg/core/scenarios/homograph.go
+package scenarios
+
+import (
+	"strings"
+
+	"golang.org/x/net/idna"
+	"golang.org/x/net/publicsuffix"
+)
+
+// confusables maps common Unicode look-alikes (and multi-character
+// look-alikes like "rn" for "m") to their plain ASCII equivalent, so that
+// e.g. "аpple.com" (Cyrillic а) and "rnicrosoft.com" skeletonize to the same
+// string as the brand they're impersonating.
+var confusables = map[string]string{
+	"а": "a", // Cyrillic а (U+0430)
+	"е": "e", // Cyrillic е (U+0435)
+	"о": "o", // Cyrillic о (U+043E)
+	"р": "p", // Cyrillic р (U+0440)
+	"с": "c", // Cyrillic с (U+0441)
+	"у": "y", // Cyrillic у (U+0443)
+	"х": "x", // Cyrillic х (U+0445)
+	"і": "i", // Cyrillic і (U+0456)
+	"0": "o",
+	"1": "l",
+	"3": "e",
+	"5": "s",
+	"rn": "m",
+	"vv": "w",
+}
+
+// skeleton normalizes domain for similarity comparison: it punycode-decodes
+// any "xn--" labels, lowercases, and replaces confusable characters/digraphs
+// with their ASCII look-alike so that visually similar domains collapse to
+// the same skeleton.
+func skeleton(domain string) string {
+	decoded, err := idna.ToUnicode(strings.ToLower(domain))
+	if err != nil {
+		decoded = strings.ToLower(domain)
+	}
+
+	// Replace multi-rune confusables first so they aren't partially
+	// consumed by the single-rune pass below.
+	for from, to := range confusables {
+		if len(from) > 1 {
+			decoded = strings.ReplaceAll(decoded, from, to)
+		}
+	}
+
+	var b strings.Builder
+	for _, r := range decoded {
+		if repl, ok := confusables[string(r)]; ok {
+			b.WriteString(repl)
+			continue
+		}
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}
+
+// registrable strips the public suffix (TLD) from domain, returning just
+// the registrable label(s), e.g. "paypal" from "paypal.co.uk".
+func registrable(domain string) string {
+	domain = strings.ToLower(domain)
+	suffix, icann := publicsuffix.PublicSuffix(domain)
+	if !icann && suffix == domain {
+		// Unknown/private suffix equal to the whole domain - nothing to strip.
+		return domain
+	}
+
+	trimmed := strings.TrimSuffix(domain, "."+suffix)
+	trimmed = strings.TrimSuffix(trimmed, suffix)
+	trimmed = strings.TrimSuffix(trimmed, ".")
+
+	if idx := strings.LastIndex(trimmed, "."); idx != -1 {
+		trimmed = trimmed[idx+1:]
+	}
+
+	return trimmed
+}
+
+// damerauLevenshtein computes the Damerau-Levenshtein edit distance between
+// a and b (insertions, deletions, substitutions, and adjacent transpositions
+// all cost 1).
+func damerauLevenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	lenA, lenB := len(ra), len(rb)
+
+	d := make([][]int, lenA+1)
+	for i := range d {
+		d[i] = make([]int, lenB+1)
+		d[i][0] = i
+	}
+	for j := 0; j <= lenB; j++ {
+		d[0][j] = j
+	}
+
+	for i := 1; i <= lenA; i++ {
+		for j := 1; j <= lenB; j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+
+			d[i][j] = min3(
+				d[i-1][j]+1,      // deletion
+				d[i][j-1]+1,      // insertion
+				d[i-1][j-1]+cost, // substitution
+			)
+
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				d[i][j] = min2(d[i][j], d[i-2][j-2]+cost) // transposition
+			}
+		}
+	}
+
+	return d[lenA][lenB]
+}
+
+func min2(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func min3(a, b, c int) int {
+	return min2(min2(a, b), c)
+}
+
+// evaluateSimilarity runs the scenario's Similarity rule, if enabled,
+// against domain's registrable label. The distance is measured between the
+// *raw* (punycode-decoded but unskeletonized) registrable label and the
+// skeletonized brand, not
+// between two skeletons - skeletonizing both sides collapses a confusable
+// substitution (e.g. "paypa1" -> "paypal") into the brand's own spelling,
+// which would always measure a distance of 0 and defeat the very
+// similarity check this is supposed to catch. domainSkeleton is still
+// returned skeletonized, since callers use it as a normalized display form.
+func evaluateSimilarity(s *Scenario, domain string) (matched bool, domainSkeleton string, distance int) {
+	if !s.Similarity.Enabled || s.Brand == "" {
+		return false, "", 0
+	}
+
+	registrableLabel := strings.ToLower(registrable(domain))
+	if decoded, err := idna.ToUnicode(registrableLabel); err == nil {
+		registrableLabel = decoded
+	}
+	domainSkeleton = skeleton(registrableLabel)
+	brandSkeleton := skeleton(s.Brand)
+
+	distance = damerauLevenshtein(registrableLabel, brandSkeleton)
+	matched = distance > 0 && distance <= maxDistanceFor(s)
+
+	return matched, domainSkeleton, distance
+}
+
+// maxDistanceFor scales the configured max distance by the brand's length
+// when the scenario didn't pin an explicit value: short brands (<=5 chars)
+// default to 1, longer ones to 2, matching typical typosquat tooling.
+func maxDistanceFor(s *Scenario) int {
+	if s.Similarity.MaxDistance > 0 {
+		return s.Similarity.MaxDistance
+	}
+	if len(s.Brand) <= 5 {
+		return 1
+	}
+	return 2
+}
@@ -0,0 +1,84 @@
+package scenarios
+
+import "testing"
+
+func TestDamerauLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{"paypal", "paypal", 0},
+		{"paypal", "paypa1", 1},
+		{"apple", "aple", 1},
+		{"microsoft", "micorsoft", 1}, // transposition
+		{"google", "gooogle", 1},
+	}
+
+	for _, tt := range tests {
+		if got := damerauLevenshtein(tt.a, tt.b); got != tt.expected {
+			t.Errorf("damerauLevenshtein(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.expected)
+		}
+	}
+}
+
+func TestSkeleton(t *testing.T) {
+	tests := []struct {
+		domain   string
+		expected string
+	}{
+		{"paypa1.com", "paypal.com"},
+		{"micros0ft-login.com", "microsoft-login.com"},
+		{"rnicrosoft.com", "microsoft.com"},
+	}
+
+	for _, tt := range tests {
+		if got := skeleton(tt.domain); got != tt.expected {
+			t.Errorf("skeleton(%q) = %q, want %q", tt.domain, got, tt.expected)
+		}
+	}
+}
+
+func TestSkeletonHandlesIDN(t *testing.T) {
+	// xn--pple-43d.com punycode-decodes to "аpple.com" (Cyrillic а), which
+	// should then skeletonize down to "apple.com".
+	got := skeleton("xn--pple-43d.com")
+	if got != "apple.com" {
+		t.Errorf("skeleton(punycode apple) = %q, want %q", got, "apple.com")
+	}
+}
+
+func TestEvaluateSimilarityFlagsTyposquat(t *testing.T) {
+	dir := t.TempDir()
+	writeScenario(t, dir, "paypal-similarity.yaml", `
+name: paypal-similarity
+brand: paypal
+similarity:
+  enabled: true
+  max_distance: 1
+severity: high
+action: store
+`)
+
+	engine, err := NewEngine(dir)
+	if err != nil {
+		t.Fatalf("NewEngine() error = %v", err)
+	}
+
+	realWorldPhish := []string{"paypa1.com", "micros0ft-login.com"}
+	for _, domain := range realWorldPhish[:1] { // only paypa1.com matches the paypal brand
+		matches := engine.Evaluate(domain)
+		if len(matches) != 1 {
+			t.Fatalf("Evaluate(%q): expected 1 match, got %d", domain, len(matches))
+		}
+		if !matches[0].SimilarityMatched {
+			t.Errorf("Evaluate(%q): expected similarity match", domain)
+		}
+		if matches[0].Distance != 1 {
+			t.Errorf("Evaluate(%q): expected distance 1, got %d", domain, matches[0].Distance)
+		}
+	}
+
+	if matches := engine.Evaluate("paypal.com"); len(matches) != 0 {
+		t.Errorf("Evaluate(paypal.com): expected no matches for the real domain, got %d", len(matches))
+	}
+}
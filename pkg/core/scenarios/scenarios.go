@@ -0,0 +1,229 @@
+// pkg/core/scenarios/scenarios.go
+// Package scenarios implements a YAML-driven scenario engine for matching
+// candidate domains against brand-protection rules, inspired by CrowdSec's
+// scenario hub: each scenario is a small, declarative YAML document rather
+// than a hardcoded substring check.
+package scenarios
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SimilarityRule configures the edit-distance based typosquat check. The
+// actual distance computation lives alongside the homograph detector; this
+// is just the declarative knob a scenario can turn on.
+type SimilarityRule struct {
+	Enabled     bool `yaml:"enabled"`
+	MaxDistance int  `yaml:"max_distance"`
+}
+
+// Scenario is a single brand-protection rule loaded from a YAML file.
+type Scenario struct {
+	Name       string         `yaml:"name"`
+	Brand      string         `yaml:"brand"`
+	Keywords   []string       `yaml:"keywords"`
+	Patterns   []string       `yaml:"patterns"`
+	TLDAllow   []string       `yaml:"tld_allow"`
+	TLDDeny    []string       `yaml:"tld_deny"`
+	Similarity SimilarityRule `yaml:"similarity"`
+	Severity   string         `yaml:"severity"` // low, medium, high, critical
+	Action     string         `yaml:"action"`   // log, store, emit
+
+	compiled []*regexp.Regexp
+}
+
+// Match describes a scenario that fired for a given domain.
+type Match struct {
+	Scenario        *Scenario
+	Domain          string
+	MatchedKeywords []string
+	MatchedPatterns []string
+
+	// Similarity is set when the scenario's Similarity rule fired: Skeleton
+	// is the normalized form of domain's registrable label, and Distance is
+	// the Damerau-Levenshtein distance between the raw (unskeletonized)
+	// label and the skeleton of the scenario's brand.
+	SimilarityMatched bool
+	Skeleton          string
+	Distance          int
+}
+
+// Engine holds the currently loaded scenarios and can reload them from disk
+// without restarting the process.
+type Engine struct {
+	mu        sync.RWMutex
+	dir       string
+	scenarios []*Scenario
+}
+
+// NewEngine loads every *.yaml scenario in dir and returns a ready Engine.
+func NewEngine(dir string) (*Engine, error) {
+	e := &Engine{dir: dir}
+	if err := e.Reload(); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// Reload re-reads every scenario file in the engine's directory, replacing
+// the active rule set atomically. A bad scenario file aborts the reload and
+// leaves the previously loaded scenarios in place.
+func (e *Engine) Reload() error {
+	loaded, err := loadDir(e.dir)
+	if err != nil {
+		return err
+	}
+
+	e.mu.Lock()
+	e.scenarios = loaded
+	e.mu.Unlock()
+
+	return nil
+}
+
+// Scenarios returns a snapshot of the currently loaded scenarios.
+func (e *Engine) Scenarios() []*Scenario {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	out := make([]*Scenario, len(e.scenarios))
+	copy(out, e.scenarios)
+	return out
+}
+
+// Evaluate runs every loaded scenario against domain and returns one Match
+// per scenario that fires, in declaration order (which is the engine's
+// precedence order - scenarios loaded first take precedence).
+func (e *Engine) Evaluate(domain string) []Match {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	domainLower := strings.ToLower(domain)
+	tld := tldOf(domainLower)
+
+	var matches []Match
+	for _, s := range e.scenarios {
+		if !tldAllowed(s, tld) {
+			continue
+		}
+
+		var matchedKeywords []string
+		for _, keyword := range s.Keywords {
+			if strings.Contains(domainLower, strings.ToLower(keyword)) {
+				matchedKeywords = append(matchedKeywords, keyword)
+			}
+		}
+
+		var matchedPatterns []string
+		for i, re := range s.compiled {
+			if re.MatchString(domainLower) {
+				matchedPatterns = append(matchedPatterns, s.Patterns[i])
+			}
+		}
+
+		similarityMatched, domainSkeleton, distance := evaluateSimilarity(s, domainLower)
+
+		if len(matchedKeywords) == 0 && len(matchedPatterns) == 0 && !similarityMatched {
+			continue
+		}
+
+		matches = append(matches, Match{
+			Scenario:          s,
+			Domain:            domain,
+			MatchedKeywords:   matchedKeywords,
+			MatchedPatterns:   matchedPatterns,
+			SimilarityMatched: similarityMatched,
+			Skeleton:          domainSkeleton,
+			Distance:          distance,
+		})
+	}
+
+	return matches
+}
+
+func tldOf(domain string) string {
+	idx := strings.LastIndex(domain, ".")
+	if idx == -1 {
+		return ""
+	}
+	return domain[idx+1:]
+}
+
+func tldAllowed(s *Scenario, tld string) bool {
+	for _, denied := range s.TLDDeny {
+		if strings.EqualFold(denied, tld) {
+			return false
+		}
+	}
+
+	if len(s.TLDAllow) == 0 {
+		return true
+	}
+	for _, allowed := range s.TLDAllow {
+		if strings.EqualFold(allowed, tld) {
+			return true
+		}
+	}
+	return false
+}
+
+// loadDir reads every *.yaml/*.yml file in dir and parses it as a Scenario.
+func loadDir(dir string) ([]*Scenario, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read scenarios directory %s: %w", dir, err)
+	}
+
+	var loaded []*Scenario
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		ext := filepath.Ext(entry.Name())
+		if ext != ".yaml" && ext != ".yml" {
+			continue
+		}
+
+		path := filepath.Join(dir, entry.Name())
+		scenario, err := loadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load scenario %s: %w", path, err)
+		}
+		loaded = append(loaded, scenario)
+	}
+
+	return loaded, nil
+}
+
+func loadFile(path string) (*Scenario, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var scenario Scenario
+	if err := yaml.Unmarshal(data, &scenario); err != nil {
+		return nil, fmt.Errorf("invalid yaml: %w", err)
+	}
+
+	if scenario.Name == "" {
+		return nil, fmt.Errorf("scenario is missing a name")
+	}
+
+	for _, pattern := range scenario.Patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+		}
+		scenario.compiled = append(scenario.compiled, re)
+	}
+
+	return &scenario, nil
+}
@@ -0,0 +1,126 @@
+package scenarios
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeScenario(t *testing.T, dir, filename, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, filename), []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write scenario fixture: %v", err)
+	}
+}
+
+func TestEngineEvaluate(t *testing.T) {
+	dir := t.TempDir()
+
+	writeScenario(t, dir, "paypal-keyword.yaml", `
+name: paypal-keyword
+brand: paypal
+keywords:
+  - paypal
+severity: high
+action: store
+`)
+
+	writeScenario(t, dir, "amazon-pattern.yaml", `
+name: amazon-pattern
+brand: amazon
+patterns:
+  - "amaz[o0]n"
+tld_deny:
+  - com
+severity: medium
+action: log
+`)
+
+	engine, err := NewEngine(dir)
+	if err != nil {
+		t.Fatalf("NewEngine() error = %v", err)
+	}
+
+	t.Run("matches by keyword", func(t *testing.T) {
+		matches := engine.Evaluate("secure-paypal-login.net")
+		if len(matches) != 1 {
+			t.Fatalf("expected 1 match, got %d", len(matches))
+		}
+		if matches[0].Scenario.Name != "paypal-keyword" {
+			t.Errorf("expected paypal-keyword to match, got %s", matches[0].Scenario.Name)
+		}
+	})
+
+	t.Run("matches by pattern", func(t *testing.T) {
+		matches := engine.Evaluate("amaz0n-support.net")
+		if len(matches) != 1 {
+			t.Fatalf("expected 1 match, got %d", len(matches))
+		}
+		if matches[0].Scenario.Name != "amazon-pattern" {
+			t.Errorf("expected amazon-pattern to match, got %s", matches[0].Scenario.Name)
+		}
+	})
+
+	t.Run("tld_deny excludes denied tld", func(t *testing.T) {
+		matches := engine.Evaluate("amaz0n-support.com")
+		if len(matches) != 0 {
+			t.Fatalf("expected 0 matches for denied tld, got %d", len(matches))
+		}
+	})
+
+	t.Run("no scenarios match unrelated domain", func(t *testing.T) {
+		matches := engine.Evaluate("example.org")
+		if len(matches) != 0 {
+			t.Fatalf("expected 0 matches, got %d", len(matches))
+		}
+	})
+}
+
+func TestEngineReload(t *testing.T) {
+	dir := t.TempDir()
+	writeScenario(t, dir, "paypal.yaml", `
+name: paypal-keyword
+brand: paypal
+keywords:
+  - paypal
+severity: high
+action: store
+`)
+
+	engine, err := NewEngine(dir)
+	if err != nil {
+		t.Fatalf("NewEngine() error = %v", err)
+	}
+	if len(engine.Scenarios()) != 1 {
+		t.Fatalf("expected 1 scenario loaded, got %d", len(engine.Scenarios()))
+	}
+
+	writeScenario(t, dir, "apple.yaml", `
+name: apple-keyword
+brand: apple
+keywords:
+  - apple
+severity: medium
+action: store
+`)
+
+	if err := engine.Reload(); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	if len(engine.Scenarios()) != 2 {
+		t.Fatalf("expected 2 scenarios after reload, got %d", len(engine.Scenarios()))
+	}
+}
+
+func TestLoadFileRejectsInvalidPattern(t *testing.T) {
+	dir := t.TempDir()
+	writeScenario(t, dir, "broken.yaml", `
+name: broken
+patterns:
+  - "("
+`)
+
+	if _, err := NewEngine(dir); err == nil {
+		t.Fatal("expected an error for an invalid regex pattern")
+	}
+}
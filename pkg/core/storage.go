@@ -1,23 +1,33 @@
 package core
 
 import (
+	"context"
 	"fmt"
+	"log/slog"
 	"sync"
 	"time"
+
+	"openbpl/internal/config"
+	"openbpl/pkg/core/metrics"
+	"openbpl/pkg/core/workers"
 )
 
-func NewStorage(storageType string) (Storage, error) {
-	switch storageType {
+// NewStorage builds the Storage backend named by cfg.Type. "sqlite" and
+// "postgres" use cfg.DSN as the file path / connection URL respectively.
+// "embedded-postgres" ignores cfg.DSN and starts a local Postgres instead,
+// for demos and development with no external service available.
+func NewStorage(cfg config.StorageConfig) (Storage, error) {
+	switch cfg.Type {
 	case "memory":
 		return NewMemoryStorage(), nil
 	case "sqlite":
-		// TODO: Implement SQLite storage
-		return nil, fmt.Errorf("SQLite storage not implemented yet")
+		return NewSQLiteStorage(cfg.DSN)
 	case "postgres":
-		// TODO: Implement PostgreSQL storage
-		return nil, fmt.Errorf("PostgreSQL storage not implemented yet")
+		return NewPostgresStorage(cfg.DSN)
+	case "embedded-postgres":
+		return NewEmbeddedPostgresStorage(context.Background())
 	default:
-		return nil, fmt.Errorf("unknown storage type: %s", storageType)
+		return nil, fmt.Errorf("unknown storage type: %s", cfg.Type)
 	}
 }
 
@@ -27,6 +37,10 @@ type MemoryStorage struct {
 	detections     []DetectionResult
 	eventIndex     map[string]int
 	detectionIndex map[string]int
+	jobs           []workers.QueuedJob
+	// Logger receives structured logs for this storage backend. If nil,
+	// slog.Default() is used.
+	Logger *slog.Logger
 }
 
 func NewMemoryStorage() *MemoryStorage {
@@ -38,7 +52,19 @@ func NewMemoryStorage() *MemoryStorage {
 	}
 }
 
+func (m *MemoryStorage) logger() *slog.Logger {
+	if m.Logger != nil {
+		return m.Logger
+	}
+	return slog.Default()
+}
+
 func (m *MemoryStorage) SaveEvent(event Event) error {
+	start := time.Now()
+	defer func() {
+		metrics.StorageSaveDuration.WithLabelValues("memory", "save_event").Observe(time.Since(start).Seconds())
+	}()
+
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
@@ -51,11 +77,17 @@ func (m *MemoryStorage) SaveEvent(event Event) error {
 	index := len(m.events)
 	m.events = append(m.events, event)
 	m.eventIndex[event.ID] = index
+	m.logger().Debug("saved event", "id", event.ID, "domain", event.Domain)
 
 	return nil
 }
 
 func (m *MemoryStorage) SaveDetection(result DetectionResult) error {
+	start := time.Now()
+	defer func() {
+		metrics.StorageSaveDuration.WithLabelValues("memory", "save_detection").Observe(time.Since(start).Seconds())
+	}()
+
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
@@ -68,6 +100,7 @@ func (m *MemoryStorage) SaveDetection(result DetectionResult) error {
 	index := len(m.detections)
 	m.detections = append(m.detections, result)
 	m.detectionIndex[result.ID] = index
+	m.logger().Debug("saved detection", "id", result.ID, "domain", result.Domain)
 
 	return nil
 }
@@ -116,6 +149,47 @@ func (m *MemoryStorage) GetDetections(filters map[string]interface{}) ([]Detecti
 	return filtered, nil
 }
 
+// EnqueueJob appends job to the in-memory work queue, generating an ID if
+// one isn't set (e.g. on first enqueue, as opposed to a requeue).
+func (m *MemoryStorage) EnqueueJob(job workers.QueuedJob) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if job.ID == "" {
+		job.ID = fmt.Sprintf("job_%d_%d", len(m.jobs), time.Now().UnixNano())
+	}
+
+	for i, existing := range m.jobs {
+		if existing.ID == job.ID {
+			m.jobs[i] = job
+			return nil
+		}
+	}
+
+	m.jobs = append(m.jobs, job)
+	return nil
+}
+
+// DequeueBatch pops up to n jobs whose NextAttempt is due.
+func (m *MemoryStorage) DequeueBatch(n int) ([]workers.QueuedJob, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	var batch []workers.QueuedJob
+	remaining := m.jobs[:0:0]
+	for _, job := range m.jobs {
+		if len(batch) < n && !job.NextAttempt.After(now) {
+			batch = append(batch, job)
+			continue
+		}
+		remaining = append(remaining, job)
+	}
+	m.jobs = remaining
+
+	return batch, nil
+}
+
 // Close closes the storage (no-op for memory storage)
 func (m *MemoryStorage) Close() error {
 	m.mu.Lock()
@@ -126,6 +200,7 @@ func (m *MemoryStorage) Close() error {
 	m.detections = nil
 	m.eventIndex = nil
 	m.detectionIndex = nil
+	m.jobs = nil
 
 	return nil
 }
@@ -0,0 +1,346 @@
+// pkg/core/storage_postgres.go
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"openbpl/internal/database"
+	"openbpl/pkg/core/workers"
+)
+
+const postgresSchema = `
+CREATE TABLE IF NOT EXISTS events (
+	id TEXT PRIMARY KEY,
+	source TEXT NOT NULL,
+	type TEXT NOT NULL,
+	domain TEXT NOT NULL,
+	timestamp TIMESTAMPTZ NOT NULL,
+	data JSONB,
+	metadata JSONB
+);
+CREATE INDEX IF NOT EXISTS idx_events_source ON events(source);
+CREATE INDEX IF NOT EXISTS idx_events_type ON events(type);
+CREATE INDEX IF NOT EXISTS idx_events_domain ON events(domain);
+
+CREATE TABLE IF NOT EXISTS detections (
+	id TEXT PRIMARY KEY,
+	event_id TEXT NOT NULL,
+	domain TEXT NOT NULL,
+	is_threat BOOLEAN NOT NULL,
+	confidence DOUBLE PRECISION,
+	brand TEXT,
+	rule TEXT,
+	detected_at TIMESTAMPTZ NOT NULL,
+	metadata JSONB
+);
+CREATE INDEX IF NOT EXISTS idx_detections_domain ON detections(domain);
+CREATE INDEX IF NOT EXISTS idx_detections_brand ON detections(brand);
+CREATE INDEX IF NOT EXISTS idx_detections_rule ON detections(rule);
+CREATE INDEX IF NOT EXISTS idx_detections_is_threat ON detections(is_threat);
+
+CREATE TABLE IF NOT EXISTS jobs (
+	id TEXT PRIMARY KEY,
+	kind TEXT NOT NULL,
+	key TEXT NOT NULL,
+	payload JSONB,
+	attempts INTEGER NOT NULL DEFAULT 0,
+	next_attempt TIMESTAMPTZ NOT NULL,
+	created_at TIMESTAMPTZ NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_jobs_next_attempt ON jobs(next_attempt);
+`
+
+// PostgresStorage persists events and detections in PostgreSQL.
+type PostgresStorage struct {
+	db *database.DB
+	// stopEmbedded, if set (only for NewEmbeddedPostgresStorage), shuts
+	// down the embedded Postgres server backing db; Close calls it after
+	// closing db.
+	stopEmbedded func()
+}
+
+// NewPostgresStorage connects to PostgreSQL via the shared connection pool
+// helper and ensures the events/detections schema exists.
+func NewPostgresStorage(databaseURL string) (*PostgresStorage, error) {
+	db, err := database.Connect(databaseURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to postgres storage: %w", err)
+	}
+
+	if _, err := db.Exec(postgresSchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to migrate postgres schema: %w", err)
+	}
+
+	return &PostgresStorage{db: db}, nil
+}
+
+func (p *PostgresStorage) SaveEvent(event Event) error {
+	if event.ID == "" {
+		event.ID = fmt.Sprintf("event_%d", time.Now().UnixNano())
+	}
+
+	data, err := json.Marshal(event.Data)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event data: %w", err)
+	}
+	metadata, err := json.Marshal(event.Metadata)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event metadata: %w", err)
+	}
+
+	_, err = p.db.Exec(
+		`INSERT INTO events (id, source, type, domain, timestamp, data, metadata)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7)
+		 ON CONFLICT (id) DO NOTHING`,
+		event.ID, event.Source, event.Type, event.Domain, event.Timestamp, data, metadata,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save event: %w", err)
+	}
+
+	return nil
+}
+
+func (p *PostgresStorage) SaveDetection(result DetectionResult) error {
+	if result.ID == "" {
+		result.ID = fmt.Sprintf("detection_%d", time.Now().UnixNano())
+	}
+
+	metadata, err := json.Marshal(result.Metadata)
+	if err != nil {
+		return fmt.Errorf("failed to marshal detection metadata: %w", err)
+	}
+
+	_, err = p.db.Exec(
+		`INSERT INTO detections (id, event_id, domain, is_threat, confidence, brand, rule, detected_at, metadata)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		 ON CONFLICT (id) DO NOTHING`,
+		result.ID, result.EventID, result.Domain, result.IsThreat, result.Confidence,
+		result.Brand, result.Rule, result.DetectedAt, metadata,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save detection: %w", err)
+	}
+
+	return nil
+}
+
+// GetEvents retrieves events, translating the filter map into WHERE clauses
+// against the indexed source/type/domain columns.
+func (p *PostgresStorage) GetEvents(filters map[string]interface{}) ([]Event, error) {
+	where, args := eventFilterClause(filters, "$")
+	query := "SELECT id, source, type, domain, timestamp, data, metadata FROM events" + where + " ORDER BY timestamp DESC"
+
+	rows, err := p.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []Event
+	for rows.Next() {
+		var event Event
+		var data, metadata []byte
+		if err := rows.Scan(&event.ID, &event.Source, &event.Type, &event.Domain, &event.Timestamp, &data, &metadata); err != nil {
+			return nil, fmt.Errorf("failed to scan event: %w", err)
+		}
+		if err := unmarshalJSONMap(data, &event.Data); err != nil {
+			return nil, err
+		}
+		if err := unmarshalJSONMap(metadata, &event.Metadata); err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+
+	return events, rows.Err()
+}
+
+// GetDetections retrieves detections, translating the filter map into WHERE
+// clauses against the indexed domain/brand/rule/is_threat columns.
+func (p *PostgresStorage) GetDetections(filters map[string]interface{}) ([]DetectionResult, error) {
+	where, args := detectionFilterClause(filters, "$")
+	query := "SELECT id, event_id, domain, is_threat, confidence, brand, rule, detected_at, metadata FROM detections" + where + " ORDER BY detected_at DESC"
+
+	rows, err := p.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query detections: %w", err)
+	}
+	defer rows.Close()
+
+	var detections []DetectionResult
+	for rows.Next() {
+		var result DetectionResult
+		var metadata []byte
+		if err := rows.Scan(&result.ID, &result.EventID, &result.Domain, &result.IsThreat,
+			&result.Confidence, &result.Brand, &result.Rule, &result.DetectedAt, &metadata); err != nil {
+			return nil, fmt.Errorf("failed to scan detection: %w", err)
+		}
+		if err := unmarshalJSONMap(metadata, &result.Metadata); err != nil {
+			return nil, err
+		}
+		detections = append(detections, result)
+	}
+
+	return detections, rows.Err()
+}
+
+// EnqueueJob upserts job into the durable jobs table, so a requeue (same
+// ID, later NextAttempt) replaces the prior row instead of duplicating it.
+func (p *PostgresStorage) EnqueueJob(job workers.QueuedJob) error {
+	if job.ID == "" {
+		job.ID = fmt.Sprintf("job_%d", time.Now().UnixNano())
+	}
+	if job.CreatedAt.IsZero() {
+		job.CreatedAt = time.Now()
+	}
+
+	_, err := p.db.Exec(
+		`INSERT INTO jobs (id, kind, key, payload, attempts, next_attempt, created_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7)
+		 ON CONFLICT (id) DO UPDATE SET
+			attempts = excluded.attempts,
+			next_attempt = excluded.next_attempt`,
+		job.ID, job.Kind, job.Key, job.Payload, job.Attempts, job.NextAttempt, job.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to enqueue job: %w", err)
+	}
+
+	return nil
+}
+
+// DequeueBatch pops up to n due jobs (next_attempt <= now), deleting them
+// within a transaction so concurrent workers don't race for the same rows.
+func (p *PostgresStorage) DequeueBatch(n int) ([]workers.QueuedJob, error) {
+	tx, err := p.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin dequeue transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(
+		`SELECT id, kind, key, payload, attempts, next_attempt, created_at
+		 FROM jobs WHERE next_attempt <= $1 ORDER BY next_attempt LIMIT $2
+		 FOR UPDATE SKIP LOCKED`,
+		time.Now(), n,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query due jobs: %w", err)
+	}
+
+	var batch []workers.QueuedJob
+	for rows.Next() {
+		var job workers.QueuedJob
+		if err := rows.Scan(&job.ID, &job.Kind, &job.Key, &job.Payload, &job.Attempts, &job.NextAttempt, &job.CreatedAt); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan job: %w", err)
+		}
+		batch = append(batch, job)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, job := range batch {
+		if _, err := tx.Exec("DELETE FROM jobs WHERE id = $1", job.ID); err != nil {
+			return nil, fmt.Errorf("failed to delete dequeued job: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit dequeue transaction: %w", err)
+	}
+
+	return batch, nil
+}
+
+func (p *PostgresStorage) Close() error {
+	err := p.db.Close()
+	if p.stopEmbedded != nil {
+		p.stopEmbedded()
+	}
+	return err
+}
+
+// NewEmbeddedPostgresStorage starts a local Postgres via
+// database.StartEmbedded instead of connecting to an external one, for
+// demos and development where there's no Postgres already running. Closing
+// the returned PostgresStorage also stops the embedded server.
+func NewEmbeddedPostgresStorage(ctx context.Context) (*PostgresStorage, error) {
+	opts := database.DefaultEmbeddedOptions()
+	opts.SchemaSQL = postgresSchema
+
+	db, stop, err := database.StartEmbedded(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start embedded postgres storage: %w", err)
+	}
+
+	return &PostgresStorage{db: db, stopEmbedded: stop}, nil
+}
+
+// unmarshalJSONMap decodes a nullable JSON column into a map, leaving the
+// destination nil when the column was empty.
+func unmarshalJSONMap(raw []byte, dest *map[string]interface{}) error {
+	if len(raw) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(raw, dest); err != nil {
+		return fmt.Errorf("failed to unmarshal json column: %w", err)
+	}
+	return nil
+}
+
+// eventFilterClause builds a "WHERE ..." clause (or "" when there are no
+// recognized filters) for the source/type/domain event columns, using
+// placeholder style ("$" for postgres, "?" for sqlite).
+func eventFilterClause(filters map[string]interface{}, placeholder string) (string, []interface{}) {
+	columns := map[string]string{
+		"source": "source",
+		"type":   "type",
+		"domain": "domain",
+	}
+	return filterClause(filters, columns, placeholder)
+}
+
+// detectionFilterClause builds a "WHERE ..." clause for the
+// domain/brand/rule/is_threat detection columns.
+func detectionFilterClause(filters map[string]interface{}, placeholder string) (string, []interface{}) {
+	columns := map[string]string{
+		"domain":    "domain",
+		"brand":     "brand",
+		"rule":      "rule",
+		"is_threat": "is_threat",
+	}
+	return filterClause(filters, columns, placeholder)
+}
+
+func filterClause(filters map[string]interface{}, columns map[string]string, placeholder string) (string, []interface{}) {
+	var clauses []string
+	var args []interface{}
+
+	for key, column := range columns {
+		value, ok := filters[key]
+		if !ok {
+			continue
+		}
+		args = append(args, value)
+		if placeholder == "$" {
+			clauses = append(clauses, fmt.Sprintf("%s = $%d", column, len(args)))
+		} else {
+			clauses = append(clauses, fmt.Sprintf("%s = ?", column))
+		}
+	}
+
+	if len(clauses) == 0 {
+		return "", nil
+	}
+
+	return " WHERE " + strings.Join(clauses, " AND "), args
+}
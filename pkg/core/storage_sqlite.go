@@ -0,0 +1,260 @@
+// pkg/core/storage_sqlite.go
+package core
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+
+	"openbpl/pkg/core/workers"
+)
+
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS events (
+	id TEXT PRIMARY KEY,
+	source TEXT NOT NULL,
+	type TEXT NOT NULL,
+	domain TEXT NOT NULL,
+	timestamp DATETIME NOT NULL,
+	data TEXT,
+	metadata TEXT
+);
+CREATE INDEX IF NOT EXISTS idx_events_source ON events(source);
+CREATE INDEX IF NOT EXISTS idx_events_type ON events(type);
+CREATE INDEX IF NOT EXISTS idx_events_domain ON events(domain);
+
+CREATE TABLE IF NOT EXISTS detections (
+	id TEXT PRIMARY KEY,
+	event_id TEXT NOT NULL,
+	domain TEXT NOT NULL,
+	is_threat BOOLEAN NOT NULL,
+	confidence REAL,
+	brand TEXT,
+	rule TEXT,
+	detected_at DATETIME NOT NULL,
+	metadata TEXT
+);
+CREATE INDEX IF NOT EXISTS idx_detections_domain ON detections(domain);
+CREATE INDEX IF NOT EXISTS idx_detections_brand ON detections(brand);
+CREATE INDEX IF NOT EXISTS idx_detections_rule ON detections(rule);
+CREATE INDEX IF NOT EXISTS idx_detections_is_threat ON detections(is_threat);
+
+CREATE TABLE IF NOT EXISTS jobs (
+	id TEXT PRIMARY KEY,
+	kind TEXT NOT NULL,
+	key TEXT NOT NULL,
+	payload TEXT,
+	attempts INTEGER NOT NULL DEFAULT 0,
+	next_attempt DATETIME NOT NULL,
+	created_at DATETIME NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_jobs_next_attempt ON jobs(next_attempt);
+`
+
+// SQLiteStorage persists events and detections in a local SQLite file,
+// intended for single-node deployments that still want durability across
+// restarts without standing up PostgreSQL.
+type SQLiteStorage struct {
+	db *sql.DB
+}
+
+// NewSQLiteStorage opens (creating if necessary) the SQLite database at
+// path and ensures the events/detections schema exists.
+func NewSQLiteStorage(path string) (*SQLiteStorage, error) {
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open sqlite storage: %w", err)
+	}
+
+	// SQLite only supports a single writer at a time.
+	db.SetMaxOpenConns(1)
+
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to migrate sqlite schema: %w", err)
+	}
+
+	return &SQLiteStorage{db: db}, nil
+}
+
+func (s *SQLiteStorage) SaveEvent(event Event) error {
+	if event.ID == "" {
+		event.ID = fmt.Sprintf("event_%d", time.Now().UnixNano())
+	}
+
+	data, err := json.Marshal(event.Data)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event data: %w", err)
+	}
+	metadata, err := json.Marshal(event.Metadata)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event metadata: %w", err)
+	}
+
+	_, err = s.db.Exec(
+		`INSERT OR IGNORE INTO events (id, source, type, domain, timestamp, data, metadata)
+		 VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		event.ID, event.Source, event.Type, event.Domain, event.Timestamp, data, metadata,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save event: %w", err)
+	}
+
+	return nil
+}
+
+func (s *SQLiteStorage) SaveDetection(result DetectionResult) error {
+	if result.ID == "" {
+		result.ID = fmt.Sprintf("detection_%d", time.Now().UnixNano())
+	}
+
+	metadata, err := json.Marshal(result.Metadata)
+	if err != nil {
+		return fmt.Errorf("failed to marshal detection metadata: %w", err)
+	}
+
+	_, err = s.db.Exec(
+		`INSERT OR IGNORE INTO detections (id, event_id, domain, is_threat, confidence, brand, rule, detected_at, metadata)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		result.ID, result.EventID, result.Domain, result.IsThreat, result.Confidence,
+		result.Brand, result.Rule, result.DetectedAt, metadata,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save detection: %w", err)
+	}
+
+	return nil
+}
+
+func (s *SQLiteStorage) GetEvents(filters map[string]interface{}) ([]Event, error) {
+	where, args := eventFilterClause(filters, "?")
+	query := "SELECT id, source, type, domain, timestamp, data, metadata FROM events" + where + " ORDER BY timestamp DESC"
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []Event
+	for rows.Next() {
+		var event Event
+		var data, metadata []byte
+		if err := rows.Scan(&event.ID, &event.Source, &event.Type, &event.Domain, &event.Timestamp, &data, &metadata); err != nil {
+			return nil, fmt.Errorf("failed to scan event: %w", err)
+		}
+		if err := unmarshalJSONMap(data, &event.Data); err != nil {
+			return nil, err
+		}
+		if err := unmarshalJSONMap(metadata, &event.Metadata); err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+
+	return events, rows.Err()
+}
+
+func (s *SQLiteStorage) GetDetections(filters map[string]interface{}) ([]DetectionResult, error) {
+	where, args := detectionFilterClause(filters, "?")
+	query := "SELECT id, event_id, domain, is_threat, confidence, brand, rule, detected_at, metadata FROM detections" + where + " ORDER BY detected_at DESC"
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query detections: %w", err)
+	}
+	defer rows.Close()
+
+	var detections []DetectionResult
+	for rows.Next() {
+		var result DetectionResult
+		var metadata []byte
+		if err := rows.Scan(&result.ID, &result.EventID, &result.Domain, &result.IsThreat,
+			&result.Confidence, &result.Brand, &result.Rule, &result.DetectedAt, &metadata); err != nil {
+			return nil, fmt.Errorf("failed to scan detection: %w", err)
+		}
+		if err := unmarshalJSONMap(metadata, &result.Metadata); err != nil {
+			return nil, err
+		}
+		detections = append(detections, result)
+	}
+
+	return detections, rows.Err()
+}
+
+// EnqueueJob upserts job into the durable jobs table, so a requeue (same
+// ID, later NextAttempt) replaces the prior row instead of duplicating it.
+func (s *SQLiteStorage) EnqueueJob(job workers.QueuedJob) error {
+	if job.ID == "" {
+		job.ID = fmt.Sprintf("job_%d", time.Now().UnixNano())
+	}
+	if job.CreatedAt.IsZero() {
+		job.CreatedAt = time.Now()
+	}
+
+	_, err := s.db.Exec(
+		`INSERT INTO jobs (id, kind, key, payload, attempts, next_attempt, created_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?)
+		 ON CONFLICT(id) DO UPDATE SET
+			attempts = excluded.attempts,
+			next_attempt = excluded.next_attempt`,
+		job.ID, job.Kind, job.Key, job.Payload, job.Attempts, job.NextAttempt, job.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to enqueue job: %w", err)
+	}
+
+	return nil
+}
+
+// DequeueBatch pops up to n due jobs (next_attempt <= now) in a single
+// transaction so concurrent workers don't race for the same rows.
+func (s *SQLiteStorage) DequeueBatch(n int) ([]workers.QueuedJob, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin dequeue transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(
+		`SELECT id, kind, key, payload, attempts, next_attempt, created_at
+		 FROM jobs WHERE next_attempt <= ? ORDER BY next_attempt LIMIT ?`,
+		time.Now(), n,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query due jobs: %w", err)
+	}
+
+	var batch []workers.QueuedJob
+	for rows.Next() {
+		var job workers.QueuedJob
+		if err := rows.Scan(&job.ID, &job.Kind, &job.Key, &job.Payload, &job.Attempts, &job.NextAttempt, &job.CreatedAt); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan job: %w", err)
+		}
+		batch = append(batch, job)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, job := range batch {
+		if _, err := tx.Exec("DELETE FROM jobs WHERE id = ?", job.ID); err != nil {
+			return nil, fmt.Errorf("failed to delete dequeued job: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit dequeue transaction: %w", err)
+	}
+
+	return batch, nil
+}
+
+func (s *SQLiteStorage) Close() error {
+	return s.db.Close()
+}
@@ -0,0 +1,159 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"openbpl/pkg/core/workers"
+)
+
+// storageBackends returns the Storage implementations to exercise with the
+// shared behavior suite below. Postgres is skipped when no test database is
+// reachable, mirroring internal/database's test helper.
+func storageBackends(t *testing.T) map[string]Storage {
+	t.Helper()
+
+	backends := map[string]Storage{
+		"memory": NewMemoryStorage(),
+	}
+
+	sqlitePath := filepath.Join(t.TempDir(), "storage_test.db")
+	sqliteStorage, err := NewSQLiteStorage(sqlitePath)
+	if err != nil {
+		t.Fatalf("failed to open sqlite storage: %v", err)
+	}
+	backends["sqlite"] = sqliteStorage
+
+	if testURL := os.Getenv("OPENBPL_TEST_POSTGRES_URL"); testURL != "" {
+		postgresStorage, err := NewPostgresStorage(testURL)
+		if err != nil {
+			t.Fatalf("failed to connect to postgres storage: %v", err)
+		}
+		backends["postgres"] = postgresStorage
+	}
+
+	return backends
+}
+
+func TestStorageBackends(t *testing.T) {
+	for name, storage := range storageBackends(t) {
+		t.Run(name, func(t *testing.T) {
+			defer storage.Close()
+
+			event := Event{
+				Source:    "certstream",
+				Type:      "certificate_update",
+				Domain:    "paypa1-login.com",
+				Timestamp: time.Now().UTC().Truncate(time.Second),
+				Data: map[string]interface{}{
+					"cn": "paypa1-login.com",
+				},
+				Metadata: map[string]interface{}{
+					"matched_keywords": []interface{}{"paypal"},
+				},
+			}
+			if err := storage.SaveEvent(event); err != nil {
+				t.Fatalf("SaveEvent() error = %v", err)
+			}
+
+			result := DetectionResult{
+				EventID:    "evt-1",
+				Domain:     "paypa1-login.com",
+				IsThreat:   true,
+				Confidence: 0.92,
+				Brand:      "paypal",
+				Rule:       "typosquat",
+				DetectedAt: time.Now().UTC().Truncate(time.Second),
+				Metadata: map[string]interface{}{
+					"distance": 1,
+				},
+			}
+			if err := storage.SaveDetection(result); err != nil {
+				t.Fatalf("SaveDetection() error = %v", err)
+			}
+
+			events, err := storage.GetEvents(map[string]interface{}{"domain": "paypa1-login.com"})
+			if err != nil {
+				t.Fatalf("GetEvents() error = %v", err)
+			}
+			if len(events) != 1 {
+				t.Fatalf("expected 1 event, got %d", len(events))
+			}
+			if events[0].Source != "certstream" {
+				t.Errorf("expected source certstream, got %s", events[0].Source)
+			}
+
+			detections, err := storage.GetDetections(map[string]interface{}{"brand": "paypal", "is_threat": true})
+			if err != nil {
+				t.Fatalf("GetDetections() error = %v", err)
+			}
+			if len(detections) != 1 {
+				t.Fatalf("expected 1 detection, got %d", len(detections))
+			}
+			if detections[0].Rule != "typosquat" {
+				t.Errorf("expected rule typosquat, got %s", detections[0].Rule)
+			}
+
+			noMatches, err := storage.GetEvents(map[string]interface{}{"domain": "example.com"})
+			if err != nil {
+				t.Fatalf("GetEvents() error = %v", err)
+			}
+			if len(noMatches) != 0 {
+				t.Errorf("expected 0 events for unmatched domain, got %d", len(noMatches))
+			}
+		})
+	}
+}
+
+func TestStorageJobQueue(t *testing.T) {
+	for name, storage := range storageBackends(t) {
+		t.Run(name, func(t *testing.T) {
+			defer storage.Close()
+
+			past := workers.QueuedJob{
+				ID:          "job-due",
+				Kind:        "event",
+				Key:         "paypa1-login.com",
+				Payload:     []byte(`{"domain":"paypa1-login.com"}`),
+				NextAttempt: time.Now().Add(-time.Minute),
+				CreatedAt:   time.Now(),
+			}
+			future := workers.QueuedJob{
+				ID:          "job-not-due",
+				Kind:        "detection",
+				Key:         "example.com",
+				Payload:     []byte(`{}`),
+				NextAttempt: time.Now().Add(time.Hour),
+				CreatedAt:   time.Now(),
+			}
+
+			if err := storage.EnqueueJob(past); err != nil {
+				t.Fatalf("EnqueueJob() error = %v", err)
+			}
+			if err := storage.EnqueueJob(future); err != nil {
+				t.Fatalf("EnqueueJob() error = %v", err)
+			}
+
+			batch, err := storage.DequeueBatch(10)
+			if err != nil {
+				t.Fatalf("DequeueBatch() error = %v", err)
+			}
+			if len(batch) != 1 {
+				t.Fatalf("expected 1 due job, got %d", len(batch))
+			}
+			if batch[0].ID != "job-due" {
+				t.Errorf("expected job-due, got %s", batch[0].ID)
+			}
+
+			empty, err := storage.DequeueBatch(10)
+			if err != nil {
+				t.Fatalf("DequeueBatch() error = %v", err)
+			}
+			if len(empty) != 0 {
+				t.Errorf("expected the due job to have been removed, got %d", len(empty))
+			}
+		})
+	}
+}
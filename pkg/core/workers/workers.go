@@ -0,0 +1,279 @@
+// Package workers implements a bounded worker pool for processing jobs
+// pulled from a persistent, storage-backed queue, with per-host exponential
+// backoff and a circuit breaker so a single bad host can't starve the rest
+// of the pipeline.
+package workers
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Job is a unit of work keyed by its target host/domain, so retries for the
+// same bad host get coalesced onto the same backoff/circuit-breaker state.
+type Job interface {
+	// Key identifies the job's target host, e.g. a domain name.
+	Key() string
+	// Run executes the job.
+	Run(ctx context.Context) error
+	// Retryable reports whether err is worth retrying. Returning false drops
+	// the job permanently after this attempt.
+	Retryable(err error) bool
+}
+
+// QueuedJob is the storage-backed representation of a Job: its live Run/
+// Retryable behavior is stripped out, leaving just enough to persist it and
+// later reconstruct it via a Decoder.
+type QueuedJob struct {
+	ID          string
+	Kind        string // caller-defined, e.g. "event" or "detection"
+	Key         string
+	Payload     []byte
+	Attempts    int
+	NextAttempt time.Time
+	CreatedAt   time.Time
+}
+
+// Queue is the persistence the Pool needs. core.Storage implements this.
+type Queue interface {
+	EnqueueJob(job QueuedJob) error
+	DequeueBatch(n int) ([]QueuedJob, error)
+}
+
+// Decoder reconstructs a runnable Job from its persisted QueuedJob. Only the
+// caller (which defined the job kinds) can do this, so it's supplied to
+// NewPool rather than baked into this package.
+type Decoder func(QueuedJob) (Job, error)
+
+const (
+	// circuitBreakerThreshold is the number of consecutive failures
+	// against one host before its circuit breaker opens.
+	circuitBreakerThreshold = 5
+	// circuitBreakerCoolOff is how long a tripped circuit breaker stays
+	// open before jobs for that host are attempted again.
+	circuitBreakerCoolOff = 10 * time.Minute
+)
+
+// backoffFor returns the delay before retrying a job after its attempts'th
+// failure: 30s, then 5m, then capped at 1h.
+func backoffFor(attempts int) time.Duration {
+	switch {
+	case attempts <= 1:
+		return 30 * time.Second
+	case attempts == 2:
+		return 5 * time.Minute
+	default:
+		return 1 * time.Hour
+	}
+}
+
+type hostState struct {
+	consecutiveFailures int
+	circuitOpenUntil    time.Time
+}
+
+// Stats is a point-in-time snapshot of pool activity.
+type Stats struct {
+	QueueDepth   int64
+	InFlight     int64
+	HostFailures map[string]int
+}
+
+// Pool runs Size worker goroutines, each polling Queue for due jobs,
+// decoding them via Decoder, and running them.
+type Pool struct {
+	// Logger receives structured logs for queue/dispatch activity. If nil,
+	// slog.Default() is used.
+	Logger *slog.Logger
+
+	size         int
+	queue        Queue
+	decode       Decoder
+	pollInterval time.Duration
+
+	mu    sync.Mutex
+	hosts map[string]*hostState
+
+	queueDepth int64
+	inFlight   int64
+}
+
+// NewPool creates a Pool of size worker goroutines. size is clamped to at
+// least 1.
+func NewPool(size int, queue Queue, decode Decoder) *Pool {
+	if size <= 0 {
+		size = 1
+	}
+	return &Pool{
+		size:         size,
+		queue:        queue,
+		decode:       decode,
+		pollInterval: 200 * time.Millisecond,
+		hosts:        make(map[string]*hostState),
+	}
+}
+
+// logger returns p.Logger, falling back to slog.Default() when unset.
+func (p *Pool) logger() *slog.Logger {
+	if p.Logger != nil {
+		return p.Logger
+	}
+	return slog.Default()
+}
+
+// Start launches the pool's worker goroutines. It returns immediately;
+// workers run until ctx is done.
+func (p *Pool) Start(ctx context.Context) {
+	for i := 0; i < p.size; i++ {
+		go p.worker(ctx)
+	}
+}
+
+// Enqueue persists job as a QueuedJob of the given kind, keyed by key, so a
+// worker will eventually pick it up.
+func (p *Pool) Enqueue(kind, key string, payload []byte) error {
+	qj := QueuedJob{
+		ID:          fmt.Sprintf("%s_%d", kind, time.Now().UnixNano()),
+		Kind:        kind,
+		Key:         key,
+		Payload:     payload,
+		NextAttempt: time.Now(),
+		CreatedAt:   time.Now(),
+	}
+
+	if err := p.queue.EnqueueJob(qj); err != nil {
+		return fmt.Errorf("failed to enqueue job: %w", err)
+	}
+	atomic.AddInt64(&p.queueDepth, 1)
+	return nil
+}
+
+// EnqueueEvent enqueues an event-processing job (enrichment + detection).
+func (p *Pool) EnqueueEvent(key string, payload []byte) error {
+	return p.Enqueue("event", key, payload)
+}
+
+// EnqueueDetection enqueues an enforcement job for a detection result, so
+// slow enforcers (e.g. SMTP) run on the pool instead of blocking the
+// detection pipeline.
+func (p *Pool) EnqueueDetection(key string, payload []byte) error {
+	return p.Enqueue("detection", key, payload)
+}
+
+// Stats returns a snapshot of queue depth, in-flight jobs, and per-host
+// consecutive failure counts.
+func (p *Pool) Stats() Stats {
+	p.mu.Lock()
+	hostFailures := make(map[string]int, len(p.hosts))
+	for host, state := range p.hosts {
+		hostFailures[host] = state.consecutiveFailures
+	}
+	p.mu.Unlock()
+
+	return Stats{
+		QueueDepth:   atomic.LoadInt64(&p.queueDepth),
+		InFlight:     atomic.LoadInt64(&p.inFlight),
+		HostFailures: hostFailures,
+	}
+}
+
+func (p *Pool) worker(ctx context.Context) {
+	ticker := time.NewTicker(p.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			p.runOne(ctx)
+		}
+	}
+}
+
+func (p *Pool) runOne(ctx context.Context) {
+	batch, err := p.queue.DequeueBatch(1)
+	if err != nil {
+		p.logger().Error("failed to dequeue", "error", err)
+		return
+	}
+	for _, qj := range batch {
+		atomic.AddInt64(&p.queueDepth, -1)
+		p.process(ctx, qj)
+	}
+}
+
+func (p *Pool) process(ctx context.Context, qj QueuedJob) {
+	p.mu.Lock()
+	state := p.hostStateLocked(qj.Key)
+	circuitOpenUntil := state.circuitOpenUntil
+	p.mu.Unlock()
+
+	if now := time.Now(); now.Before(circuitOpenUntil) {
+		p.logger().Warn("circuit open, deferring job", "host", qj.Key, "job_id", qj.ID)
+		p.requeue(qj, circuitOpenUntil)
+		return
+	}
+
+	job, err := p.decode(qj)
+	if err != nil {
+		p.logger().Error("failed to decode job", "job_id", qj.ID, "error", err)
+		return
+	}
+
+	atomic.AddInt64(&p.inFlight, 1)
+	runErr := job.Run(ctx)
+	atomic.AddInt64(&p.inFlight, -1)
+
+	if runErr == nil {
+		p.mu.Lock()
+		p.hostStateLocked(qj.Key).consecutiveFailures = 0
+		p.mu.Unlock()
+		return
+	}
+
+	p.handleFailure(qj, job, runErr)
+}
+
+func (p *Pool) handleFailure(qj QueuedJob, job Job, runErr error) {
+	p.mu.Lock()
+	state := p.hostStateLocked(qj.Key)
+	state.consecutiveFailures++
+	if state.consecutiveFailures >= circuitBreakerThreshold {
+		state.circuitOpenUntil = time.Now().Add(circuitBreakerCoolOff)
+		p.logger().Error("tripping circuit breaker", "host", qj.Key, "consecutive_failures", state.consecutiveFailures)
+	}
+	p.mu.Unlock()
+
+	if !job.Retryable(runErr) {
+		p.logger().Error("job failed permanently", "job_id", qj.ID, "host", qj.Key, "error", runErr)
+		return
+	}
+
+	qj.Attempts++
+	next := time.Now().Add(backoffFor(qj.Attempts))
+	p.logger().Warn("job failed, retrying", "job_id", qj.ID, "host", qj.Key, "attempt", qj.Attempts, "retry_at", next, "error", runErr)
+	p.requeue(qj, next)
+}
+
+func (p *Pool) requeue(qj QueuedJob, nextAttempt time.Time) {
+	qj.NextAttempt = nextAttempt
+	if err := p.queue.EnqueueJob(qj); err != nil {
+		p.logger().Error("failed to requeue job", "job_id", qj.ID, "error", err)
+		return
+	}
+	atomic.AddInt64(&p.queueDepth, 1)
+}
+
+func (p *Pool) hostStateLocked(host string) *hostState {
+	state, ok := p.hosts[host]
+	if !ok {
+		state = &hostState{}
+		p.hosts[host] = state
+	}
+	return state
+}
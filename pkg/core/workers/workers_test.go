@@ -0,0 +1,145 @@
+package workers
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// memoryQueue is a minimal in-memory Queue for testing the Pool in
+// isolation, mirroring core.MemoryStorage's job-queue behavior.
+type memoryQueue struct {
+	mu   sync.Mutex
+	jobs []QueuedJob
+}
+
+func (q *memoryQueue) EnqueueJob(job QueuedJob) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.jobs = append(q.jobs, job)
+	return nil
+}
+
+func (q *memoryQueue) DequeueBatch(n int) ([]QueuedJob, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	now := time.Now()
+	var batch []QueuedJob
+	var remaining []QueuedJob
+	for _, job := range q.jobs {
+		if len(batch) < n && !job.NextAttempt.After(now) {
+			batch = append(batch, job)
+			continue
+		}
+		remaining = append(remaining, job)
+	}
+	q.jobs = remaining
+	return batch, nil
+}
+
+type countingJob struct {
+	key     string
+	fail    int32 // number of remaining failures before succeeding
+	runs    int32
+	succeed chan struct{}
+}
+
+func (j *countingJob) Key() string { return j.key }
+
+func (j *countingJob) Run(ctx context.Context) error {
+	atomic.AddInt32(&j.runs, 1)
+	if atomic.AddInt32(&j.fail, -1) >= 0 {
+		return errors.New("simulated failure")
+	}
+	close(j.succeed)
+	return nil
+}
+
+func (j *countingJob) Retryable(err error) bool { return true }
+
+func TestPoolRunsEnqueuedJob(t *testing.T) {
+	queue := &memoryQueue{}
+	job := &countingJob{key: "example.com", succeed: make(chan struct{})}
+
+	decode := func(qj QueuedJob) (Job, error) { return job, nil }
+
+	pool := NewPool(1, queue, decode)
+	pool.pollInterval = time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	pool.Start(ctx)
+
+	if err := pool.EnqueueEvent("example.com", []byte("{}")); err != nil {
+		t.Fatalf("EnqueueEvent returned error: %v", err)
+	}
+
+	select {
+	case <-job.succeed:
+	case <-time.After(time.Second):
+		t.Fatal("job never ran to success")
+	}
+}
+
+func TestPoolRetriesFailedJobWithBackoff(t *testing.T) {
+	queue := &memoryQueue{}
+	job := &countingJob{key: "bad-host.com", fail: 1, succeed: make(chan struct{})}
+
+	decode := func(qj QueuedJob) (Job, error) { return job, nil }
+
+	pool := NewPool(1, queue, decode)
+	pool.pollInterval = time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	pool.Start(ctx)
+
+	if err := pool.EnqueueDetection("bad-host.com", []byte("{}")); err != nil {
+		t.Fatalf("EnqueueDetection returned error: %v", err)
+	}
+
+	// The job fails once, so it should be requeued with a 30s backoff
+	// rather than retried immediately.
+	time.Sleep(20 * time.Millisecond)
+	if atomic.LoadInt32(&job.runs) != 1 {
+		t.Fatalf("expected exactly 1 run before backoff elapses, got %d", job.runs)
+	}
+
+	stats := pool.Stats()
+	if stats.HostFailures["bad-host.com"] != 1 {
+		t.Errorf("expected 1 recorded failure for bad-host.com, got %d", stats.HostFailures["bad-host.com"])
+	}
+}
+
+func TestPoolTripsCircuitBreakerAfterRepeatedFailures(t *testing.T) {
+	queue := &memoryQueue{}
+	job := &countingJob{key: "always-fails.com", fail: 1 << 30, succeed: make(chan struct{})}
+
+	decode := func(qj QueuedJob) (Job, error) { return job, nil }
+
+	pool := NewPool(1, queue, decode)
+	pool.pollInterval = time.Millisecond
+
+	// Drive failures directly instead of waiting out real backoff delays.
+	for i := 0; i < circuitBreakerThreshold; i++ {
+		qj := QueuedJob{ID: "job", Key: job.key, NextAttempt: time.Now()}
+		pool.process(context.Background(), qj)
+	}
+
+	stats := pool.Stats()
+	if stats.HostFailures["always-fails.com"] != circuitBreakerThreshold {
+		t.Fatalf("expected %d recorded failures, got %d", circuitBreakerThreshold, stats.HostFailures["always-fails.com"])
+	}
+
+	pool.mu.Lock()
+	openUntil := pool.hosts["always-fails.com"].circuitOpenUntil
+	pool.mu.Unlock()
+
+	if !openUntil.After(time.Now()) {
+		t.Error("expected circuit breaker to be open after repeated failures")
+	}
+}
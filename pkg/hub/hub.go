@@ -0,0 +1,436 @@
+// pkg/hub/hub.go
+// Package hub implements a CrowdSec-hub-style distribution mechanism for
+// community-contributed detector configs (favicon reference sets, keyword
+// lists, YARA-style rules, brand profiles): a signed index (hub-index.yaml)
+// describes the items available to install, and a local cache on disk holds
+// whatever has actually been installed, so the engine can load detector
+// inputs from the cache instead of only from config.yaml.
+package hub
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// indexSignatureSuffix is appended to IndexURL to fetch hub-index.yaml's
+// detached signature: a hex-encoded ed25519 signature over the index's raw
+// bytes, so a compromised or MITM'd index can't be swapped in unnoticed.
+const indexSignatureSuffix = ".sig"
+
+// Kind identifies what an Item contains, so callers know how to parse its
+// cached content.
+type Kind string
+
+const (
+	KindFaviconSet   Kind = "favicon_set"
+	KindKeywordList  Kind = "keyword_list"
+	KindRule         Kind = "yara_rule"
+	KindBrandProfile Kind = "brand_profile"
+)
+
+// Item describes one version of a community-contributed item, as listed in
+// hub-index.yaml. SHA256 is required - installItemLocked refuses to install
+// an item that doesn't declare one, since it's the only thing checked against
+// the downloaded content itself (the index's own signature only vouches for
+// the index, not for whatever a compromised item URL might serve).
+type Item struct {
+	Name        string `yaml:"name"`
+	Version     string `yaml:"version"`
+	Kind        Kind   `yaml:"kind"`
+	URL         string `yaml:"url"`
+	SHA256      string `yaml:"sha256"`
+	Description string `yaml:"description"`
+}
+
+// Index is the parsed form of hub-index.yaml: the full catalog of items a
+// Hub can install. A name may appear multiple times, once per version,
+// oldest first - the last match is the latest version.
+type Index struct {
+	Items []Item `yaml:"items"`
+}
+
+func (idx *Index) find(name, version string) (Item, bool) {
+	var latest Item
+	found := false
+	for _, item := range idx.Items {
+		if item.Name != name {
+			continue
+		}
+		if version != "" {
+			if item.Version == version {
+				return item, true
+			}
+			continue
+		}
+		latest = item
+		found = true
+	}
+	return latest, found
+}
+
+// Installed is the record of one item currently installed in the cache,
+// persisted to installed.lock.yaml so Update knows what to refresh and Pin
+// knows what to leave alone.
+type Installed struct {
+	Name    string `yaml:"name"`
+	Version string `yaml:"version"`
+	Kind    Kind   `yaml:"kind"`
+	SHA256  string `yaml:"sha256"`
+	Pinned  bool   `yaml:"pinned"`
+}
+
+type lockFile struct {
+	Installed []Installed `yaml:"installed"`
+}
+
+// Hub manages a local cache of community-contributed detector configs,
+// installed from a Git-backed index served over HTTP at IndexURL. The index
+// itself must be signed: every fetch verifies hub-index.yaml against
+// IndexURL+".sig" using publicKey before trusting any item in it.
+type Hub struct {
+	CacheDir string
+	IndexURL string
+
+	mu        sync.Mutex
+	publicKey ed25519.PublicKey
+	index     *Index
+	installed map[string]Installed
+}
+
+// NewHub creates a Hub backed by cacheDir, creating it if necessary and
+// loading whatever installed-item state it already holds. The index itself
+// is fetched lazily, on the first Install or Update that needs it, and is
+// verified against publicKeyHex - the hub maintainer's hex-encoded ed25519
+// public key - before any item in it is trusted. An empty publicKeyHex is
+// accepted here (so a Hub can still be constructed and List/ReferenceFavicons
+// its already-cached items), but any fetch of the index fails until one is
+// configured.
+func NewHub(cacheDir, indexURL, publicKeyHex string) (*Hub, error) {
+	publicKey, err := parsePublicKey(publicKeyHex)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(cacheDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create hub cache dir %s: %w", cacheDir, err)
+	}
+
+	h := &Hub{
+		CacheDir:  cacheDir,
+		IndexURL:  indexURL,
+		publicKey: publicKey,
+		installed: make(map[string]Installed),
+	}
+
+	if err := h.loadLock(); err != nil {
+		return nil, err
+	}
+
+	return h, nil
+}
+
+// parsePublicKey decodes a hex-encoded ed25519 public key. An empty
+// publicKeyHex returns a nil key - NewHub itself doesn't require one, but
+// fetchIndexLocked refuses to fetch (and so trust) an index without one.
+func parsePublicKey(publicKeyHex string) (ed25519.PublicKey, error) {
+	if publicKeyHex == "" {
+		return nil, nil
+	}
+
+	key, err := hex.DecodeString(publicKeyHex)
+	if err != nil {
+		return nil, fmt.Errorf("hub: invalid public_key encoding: %w", err)
+	}
+	if len(key) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("hub: public_key must be %d bytes, got %d", ed25519.PublicKeySize, len(key))
+	}
+
+	return ed25519.PublicKey(key), nil
+}
+
+// Install downloads and caches the named item. An empty version installs the
+// latest version listed in the index. The downloaded content is verified
+// against the index's SHA256 digest before it's written to the cache.
+func (h *Hub) Install(name, version string) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if err := h.ensureIndexLocked(); err != nil {
+		return err
+	}
+
+	item, ok := h.index.find(name, version)
+	if !ok {
+		if version != "" {
+			return fmt.Errorf("hub: no item %q version %q in index", name, version)
+		}
+		return fmt.Errorf("hub: no item %q in index", name)
+	}
+
+	return h.installItemLocked(item)
+}
+
+// Update re-fetches the index and re-installs every currently installed,
+// unpinned item whose latest version differs from what's cached.
+func (h *Hub) Update() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if err := h.fetchIndexLocked(); err != nil {
+		return err
+	}
+
+	for name, installed := range h.installed {
+		if installed.Pinned {
+			continue
+		}
+
+		latest, ok := h.index.find(name, "")
+		if !ok || latest.Version == installed.Version {
+			continue
+		}
+
+		if err := h.installItemLocked(latest); err != nil {
+			return fmt.Errorf("hub: failed to update %s to %s: %w", name, latest.Version, err)
+		}
+	}
+
+	return nil
+}
+
+// List returns a snapshot of every item currently installed in the cache.
+func (h *Hub) List() []Installed {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	out := make([]Installed, 0, len(h.installed))
+	for _, item := range h.installed {
+		out = append(out, item)
+	}
+	return out
+}
+
+// Pin marks name as pinned to version (installing it first if it isn't
+// already cached at that version), so Update leaves it alone.
+func (h *Hub) Pin(name, version string) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	installed, ok := h.installed[name]
+	if !ok || installed.Version != version {
+		if err := h.ensureIndexLocked(); err != nil {
+			return err
+		}
+		item, ok := h.index.find(name, version)
+		if !ok {
+			return fmt.Errorf("hub: no item %q version %q in index", name, version)
+		}
+		if err := h.installItemLocked(item); err != nil {
+			return err
+		}
+	}
+
+	installed = h.installed[name]
+	installed.Pinned = true
+	h.installed[name] = installed
+	return h.saveLockLocked()
+}
+
+// ReferenceFavicons merges the brand -> favicon URL maps of every installed
+// favicon_set item, for FaviconSimilarityDetector to use alongside (or
+// instead of) config.yaml's rules.favicon_similarity.reference_favicons.
+func (h *Hub) ReferenceFavicons() (map[string]string, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	merged := make(map[string]string)
+	for _, installed := range h.installed {
+		if installed.Kind != KindFaviconSet {
+			continue
+		}
+
+		data, err := os.ReadFile(h.itemPath(installed))
+		if err != nil {
+			return nil, fmt.Errorf("hub: failed to read cached item %s: %w", installed.Name, err)
+		}
+
+		var favicons map[string]string
+		if err := yaml.Unmarshal(data, &favicons); err != nil {
+			return nil, fmt.Errorf("hub: invalid favicon_set %s: %w", installed.Name, err)
+		}
+		for brand, url := range favicons {
+			merged[brand] = url
+		}
+	}
+
+	return merged, nil
+}
+
+func (h *Hub) ensureIndexLocked() error {
+	if h.index != nil {
+		return nil
+	}
+	return h.fetchIndexLocked()
+}
+
+func (h *Hub) fetchIndexLocked() error {
+	if h.publicKey == nil {
+		return fmt.Errorf("hub: public_key is required to verify hub-index.yaml's signature")
+	}
+
+	data, err := fetchURL(h.IndexURL)
+	if err != nil {
+		return fmt.Errorf("hub: failed to fetch index: %w", err)
+	}
+
+	sigHex, err := fetchURL(h.IndexURL + indexSignatureSuffix)
+	if err != nil {
+		return fmt.Errorf("hub: failed to fetch index signature: %w", err)
+	}
+	sig, err := hex.DecodeString(strings.TrimSpace(string(sigHex)))
+	if err != nil {
+		return fmt.Errorf("hub: invalid index signature encoding: %w", err)
+	}
+	if !ed25519.Verify(h.publicKey, data, sig) {
+		return fmt.Errorf("hub: index signature verification failed")
+	}
+
+	var index Index
+	if err := yaml.Unmarshal(data, &index); err != nil {
+		return fmt.Errorf("hub: invalid index: %w", err)
+	}
+
+	h.index = &index
+	return nil
+}
+
+// fetchURL GETs url and returns its body, erroring on a non-200 status.
+func fetchURL(url string) ([]byte, error) {
+	resp, err := http.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("got status %d", resp.StatusCode)
+	}
+
+	return io.ReadAll(resp.Body)
+}
+
+func (h *Hub) installItemLocked(item Item) error {
+	if !validItemName(item.Name) {
+		return fmt.Errorf("hub: invalid item name %q", item.Name)
+	}
+
+	content, err := fetchURL(item.URL)
+	if err != nil {
+		return fmt.Errorf("hub: failed to download %s: %w", item.Name, err)
+	}
+
+	if item.SHA256 == "" {
+		return fmt.Errorf("hub: %s@%s has no sha256 in the index, refusing to install", item.Name, item.Version)
+	}
+
+	sum := sha256.Sum256(content)
+	digest := hex.EncodeToString(sum[:])
+	if digest != item.SHA256 {
+		return fmt.Errorf("hub: %s@%s failed checksum verification: got %s, want %s", item.Name, item.Version, digest, item.SHA256)
+	}
+
+	installed := Installed{
+		Name:    item.Name,
+		Version: item.Version,
+		Kind:    item.Kind,
+		SHA256:  digest,
+	}
+	if prior, ok := h.installed[item.Name]; ok {
+		installed.Pinned = prior.Pinned
+	}
+
+	if err := os.MkdirAll(filepath.Dir(h.itemPath(installed)), 0755); err != nil {
+		return fmt.Errorf("hub: failed to create item dir: %w", err)
+	}
+	if err := os.WriteFile(h.itemPath(installed), content, 0644); err != nil {
+		return fmt.Errorf("hub: failed to write %s to cache: %w", item.Name, err)
+	}
+
+	h.installed[item.Name] = installed
+	return h.saveLockLocked()
+}
+
+// itemPath is where an installed item's content is cached, namespaced by
+// kind so items of different kinds can't collide on name alone.
+func (h *Hub) itemPath(item Installed) string {
+	return filepath.Join(h.CacheDir, "items", string(item.Kind), item.Name+".yaml")
+}
+
+// validItemName reports whether name is safe to use as an itemPath
+// component. Names are namespaced with a single "/" by convention (e.g.
+// "brand/paypal-favicons"), so a bare separator is allowed, but each
+// "/"-delimited segment must be non-empty and neither "." nor ".." - an
+// index entry (signed or not) that tried to smuggle in something like
+// "../../../etc/cron.d/x" is rejected outright rather than cleaned/escaped.
+func validItemName(name string) bool {
+	if name == "" || strings.HasPrefix(name, "/") || strings.Contains(name, `\`) {
+		return false
+	}
+	for _, segment := range strings.Split(name, "/") {
+		if segment == "" || segment == "." || segment == ".." {
+			return false
+		}
+	}
+	return true
+}
+
+func (h *Hub) lockPath() string {
+	return filepath.Join(h.CacheDir, "installed.lock.yaml")
+}
+
+func (h *Hub) loadLock() error {
+	data, err := os.ReadFile(h.lockPath())
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("hub: failed to read lock file: %w", err)
+	}
+
+	var lock lockFile
+	if err := yaml.Unmarshal(data, &lock); err != nil {
+		return fmt.Errorf("hub: invalid lock file: %w", err)
+	}
+
+	for _, item := range lock.Installed {
+		h.installed[item.Name] = item
+	}
+	return nil
+}
+
+func (h *Hub) saveLockLocked() error {
+	lock := lockFile{Installed: make([]Installed, 0, len(h.installed))}
+	for _, item := range h.installed {
+		lock.Installed = append(lock.Installed, item)
+	}
+
+	data, err := yaml.Marshal(lock)
+	if err != nil {
+		return fmt.Errorf("hub: failed to marshal lock file: %w", err)
+	}
+
+	if err := os.WriteFile(h.lockPath(), data, 0644); err != nil {
+		return fmt.Errorf("hub: failed to write lock file: %w", err)
+	}
+	return nil
+}
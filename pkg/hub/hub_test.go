@@ -0,0 +1,308 @@
+package hub
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func sha256Hex(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+// newTestKeypair generates an ed25519 keypair for signing a test index, and
+// returns the public half hex-encoded, matching NewHub's publicKeyHex arg.
+func newTestKeypair(t *testing.T) (ed25519.PrivateKey, string) {
+	t.Helper()
+
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("ed25519.GenerateKey() error = %v", err)
+	}
+	return priv, hex.EncodeToString(pub)
+}
+
+// newTestHubServer serves a hub-index.yaml listing a favicon_set item with
+// two versions, plus the item content itself, signed with priv. The returned
+// pubKeyHex is the corresponding public key to pass to NewHub.
+func newTestHubServer(t *testing.T) (server *httptest.Server, pubKeyHex string) {
+	t.Helper()
+
+	priv, pubKeyHex := newTestKeypair(t)
+
+	const v1 = "paypal: https://www.paypal.com/favicon.ico\n"
+	const v2 = "paypal: https://www.paypal.com/favicon.ico\namazon: https://www.amazon.com/favicon.ico\n"
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1.yaml", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(v1))
+	})
+	mux.HandleFunc("/v2.yaml", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(v2))
+	})
+
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	index := []byte(`
+items:
+  - name: brand/paypal-favicons
+    version: "1.0.0"
+    kind: favicon_set
+    url: ` + srv.URL + `/v1.yaml
+    sha256: ` + sha256Hex(v1) + `
+    description: Reference favicons for PayPal
+  - name: brand/paypal-favicons
+    version: "2.0.0"
+    kind: favicon_set
+    url: ` + srv.URL + `/v2.yaml
+    sha256: ` + sha256Hex(v2) + `
+    description: Reference favicons for PayPal and Amazon
+`)
+	sig := ed25519.Sign(priv, index)
+
+	mux.HandleFunc("/hub-index.yaml", func(w http.ResponseWriter, r *http.Request) {
+		w.Write(index)
+	})
+	mux.HandleFunc("/hub-index.yaml.sig", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(hex.EncodeToString(sig)))
+	})
+
+	return srv, pubKeyHex
+}
+
+func TestHubInstallAndReferenceFavicons(t *testing.T) {
+	server, pubKeyHex := newTestHubServer(t)
+
+	h, err := NewHub(t.TempDir(), server.URL+"/hub-index.yaml", pubKeyHex)
+	if err != nil {
+		t.Fatalf("NewHub() error = %v", err)
+	}
+
+	if err := h.Install("brand/paypal-favicons", "1.0.0"); err != nil {
+		t.Fatalf("Install() error = %v", err)
+	}
+
+	favicons, err := h.ReferenceFavicons()
+	if err != nil {
+		t.Fatalf("ReferenceFavicons() error = %v", err)
+	}
+	if favicons["paypal"] == "" {
+		t.Errorf("expected paypal favicon to be present")
+	}
+	if _, ok := favicons["amazon"]; ok {
+		t.Errorf("did not expect amazon favicon from v1.0.0")
+	}
+}
+
+func TestHubInstallLatestWhenVersionOmitted(t *testing.T) {
+	server, pubKeyHex := newTestHubServer(t)
+
+	h, err := NewHub(t.TempDir(), server.URL+"/hub-index.yaml", pubKeyHex)
+	if err != nil {
+		t.Fatalf("NewHub() error = %v", err)
+	}
+
+	if err := h.Install("brand/paypal-favicons", ""); err != nil {
+		t.Fatalf("Install() error = %v", err)
+	}
+
+	list := h.List()
+	if len(list) != 1 {
+		t.Fatalf("expected 1 installed item, got %d", len(list))
+	}
+	if list[0].Version != "2.0.0" {
+		t.Errorf("expected latest version 2.0.0, got %s", list[0].Version)
+	}
+}
+
+func TestHubInstallRejectsBadChecksum(t *testing.T) {
+	priv, pubKeyHex := newTestKeypair(t)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/item.yaml", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("paypal: https://www.paypal.com/favicon.ico\n"))
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	index := []byte(`
+items:
+  - name: brand/paypal-favicons
+    version: "1.0.0"
+    kind: favicon_set
+    url: ` + server.URL + `/item.yaml
+    sha256: deadbeef
+`)
+	sig := ed25519.Sign(priv, index)
+
+	mux.HandleFunc("/hub-index.yaml", func(w http.ResponseWriter, r *http.Request) {
+		w.Write(index)
+	})
+	mux.HandleFunc("/hub-index.yaml.sig", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(hex.EncodeToString(sig)))
+	})
+
+	h, err := NewHub(t.TempDir(), server.URL+"/hub-index.yaml", pubKeyHex)
+	if err != nil {
+		t.Fatalf("NewHub() error = %v", err)
+	}
+
+	if err := h.Install("brand/paypal-favicons", "1.0.0"); err == nil {
+		t.Fatal("expected a checksum verification error")
+	}
+}
+
+func TestHubInstallRejectsMissingChecksum(t *testing.T) {
+	priv, pubKeyHex := newTestKeypair(t)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/item.yaml", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("paypal: https://www.paypal.com/favicon.ico\n"))
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	index := []byte(`
+items:
+  - name: brand/paypal-favicons
+    version: "1.0.0"
+    kind: favicon_set
+    url: ` + server.URL + `/item.yaml
+`)
+	sig := ed25519.Sign(priv, index)
+
+	mux.HandleFunc("/hub-index.yaml", func(w http.ResponseWriter, r *http.Request) {
+		w.Write(index)
+	})
+	mux.HandleFunc("/hub-index.yaml.sig", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(hex.EncodeToString(sig)))
+	})
+
+	h, err := NewHub(t.TempDir(), server.URL+"/hub-index.yaml", pubKeyHex)
+	if err != nil {
+		t.Fatalf("NewHub() error = %v", err)
+	}
+
+	if err := h.Install("brand/paypal-favicons", "1.0.0"); err == nil {
+		t.Fatal("expected an error installing an item with no sha256 in the index")
+	}
+}
+
+func TestHubFetchIndexRejectsMissingPublicKey(t *testing.T) {
+	server, _ := newTestHubServer(t)
+
+	h, err := NewHub(t.TempDir(), server.URL+"/hub-index.yaml", "")
+	if err != nil {
+		t.Fatalf("NewHub() error = %v", err)
+	}
+
+	if err := h.Install("brand/paypal-favicons", "1.0.0"); err == nil {
+		t.Fatal("expected an error fetching an index with no public_key configured")
+	}
+}
+
+func TestHubFetchIndexRejectsTamperedIndex(t *testing.T) {
+	_, pubKeyHex := newTestKeypair(t)
+	// Sign with a different key than pubKeyHex, so the index's signature
+	// won't verify against the Hub's configured public key.
+	server, _ := newTestHubServer(t)
+
+	h, err := NewHub(t.TempDir(), server.URL+"/hub-index.yaml", pubKeyHex)
+	if err != nil {
+		t.Fatalf("NewHub() error = %v", err)
+	}
+
+	if err := h.Install("brand/paypal-favicons", "1.0.0"); err == nil {
+		t.Fatal("expected an error verifying an index signed by an untrusted key")
+	}
+}
+
+func TestHubInstallRejectsPathTraversalItemName(t *testing.T) {
+	priv, pubKeyHex := newTestKeypair(t)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/item.yaml", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("evil"))
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	const evilName = "../../../../tmp/hub-traversal-poc"
+	index := []byte(`
+items:
+  - name: ` + evilName + `
+    version: "1.0.0"
+    kind: favicon_set
+    url: ` + server.URL + `/item.yaml
+    sha256: ` + sha256Hex("evil") + `
+`)
+	sig := ed25519.Sign(priv, index)
+
+	mux.HandleFunc("/hub-index.yaml", func(w http.ResponseWriter, r *http.Request) {
+		w.Write(index)
+	})
+	mux.HandleFunc("/hub-index.yaml.sig", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(hex.EncodeToString(sig)))
+	})
+
+	h, err := NewHub(t.TempDir(), server.URL+"/hub-index.yaml", pubKeyHex)
+	if err != nil {
+		t.Fatalf("NewHub() error = %v", err)
+	}
+
+	if err := h.Install(evilName, "1.0.0"); err == nil {
+		t.Fatal("expected an error installing an item with a path-traversal name")
+	}
+}
+
+func TestHubPinPreventsUpdate(t *testing.T) {
+	server, pubKeyHex := newTestHubServer(t)
+
+	h, err := NewHub(t.TempDir(), server.URL+"/hub-index.yaml", pubKeyHex)
+	if err != nil {
+		t.Fatalf("NewHub() error = %v", err)
+	}
+
+	if err := h.Install("brand/paypal-favicons", "1.0.0"); err != nil {
+		t.Fatalf("Install() error = %v", err)
+	}
+	if err := h.Pin("brand/paypal-favicons", "1.0.0"); err != nil {
+		t.Fatalf("Pin() error = %v", err)
+	}
+
+	if err := h.Update(); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+
+	list := h.List()
+	if len(list) != 1 || list[0].Version != "1.0.0" {
+		t.Fatalf("expected pinned item to stay at 1.0.0, got %+v", list)
+	}
+}
+
+func TestHubUpdateRefreshesUnpinnedItems(t *testing.T) {
+	server, pubKeyHex := newTestHubServer(t)
+
+	h, err := NewHub(t.TempDir(), server.URL+"/hub-index.yaml", pubKeyHex)
+	if err != nil {
+		t.Fatalf("NewHub() error = %v", err)
+	}
+
+	if err := h.Install("brand/paypal-favicons", "1.0.0"); err != nil {
+		t.Fatalf("Install() error = %v", err)
+	}
+	if err := h.Update(); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+
+	list := h.List()
+	if len(list) != 1 || list[0].Version != "2.0.0" {
+		t.Fatalf("expected item to update to 2.0.0, got %+v", list)
+	}
+}
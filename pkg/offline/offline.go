@@ -0,0 +1,152 @@
+// Package offline implements a local, durably-queued fallback for actions
+// that couldn't be delivered when they were generated - e.g. the storage
+// backend or an enforcement API (a registrar takedown webhook) was
+// unreachable - so they can be replayed once connectivity returns, instead
+// of being silently dropped.
+package offline
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// ErrCorruptQueue is returned by Open when the queue file can't be read as
+// a valid bbolt database, including when bbolt panics opening a
+// truncated/corrupted file - that panic is recovered here and turned into
+// this error so callers can rotate the file instead of crashing.
+var ErrCorruptQueue = errors.New("offline: queue file is corrupt")
+
+var actionsBucket = []byte("actions")
+
+// Action is a unit of work that couldn't be delivered immediately. Kind is
+// caller-defined (e.g. "event" or "detection") and Payload is the
+// caller's own encoding of the thing being replayed.
+type Action struct {
+	ID        string
+	Kind      string
+	Key       string
+	Payload   []byte
+	Attempts  int
+	CreatedAt time.Time
+}
+
+// Queue is a durable, FIFO local queue of Actions backed by a single bbolt
+// file, for use when the primary storage backend or an enforcement API is
+// unreachable.
+type Queue struct {
+	db *bolt.DB
+}
+
+// Open opens (creating if necessary) the queue file at path. bbolt can
+// panic rather than return an error when handed a corrupt or truncated
+// file; that panic is recovered and reported as ErrCorruptQueue so
+// callers can rotate the file and start over rather than crash.
+func Open(path string) (q *Queue, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			q = nil
+			err = fmt.Errorf("%w: %v", ErrCorruptQueue, r)
+		}
+	}()
+
+	db, openErr := bolt.Open(path, 0600, &bolt.Options{Timeout: 5 * time.Second})
+	if openErr != nil {
+		return nil, fmt.Errorf("%w: %v", ErrCorruptQueue, openErr)
+	}
+
+	if err := db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(actionsBucket)
+		return err
+	}); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("offline: failed to initialize queue: %w", err)
+	}
+
+	return &Queue{db: db}, nil
+}
+
+// Close closes the underlying queue file.
+func (q *Queue) Close() error {
+	return q.db.Close()
+}
+
+// Enqueue appends action to the back of the queue. ID and CreatedAt are
+// filled in if unset.
+func (q *Queue) Enqueue(action Action) error {
+	if action.ID == "" {
+		action.ID = fmt.Sprintf("%s_%d", action.Kind, time.Now().UnixNano())
+	}
+	if action.CreatedAt.IsZero() {
+		action.CreatedAt = time.Now()
+	}
+
+	data, err := json.Marshal(action)
+	if err != nil {
+		return fmt.Errorf("offline: failed to marshal action: %w", err)
+	}
+
+	return q.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(actionsBucket)
+		seq, err := b.NextSequence()
+		if err != nil {
+			return err
+		}
+		return b.Put(sequenceKey(seq), data)
+	})
+}
+
+// Dequeue removes and returns up to n actions from the front of the
+// queue, oldest first.
+func (q *Queue) Dequeue(n int) ([]Action, error) {
+	var actions []Action
+
+	err := q.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(actionsBucket)
+		c := b.Cursor()
+
+		var keys [][]byte
+		for k, v := c.First(); k != nil && len(actions) < n; k, v = c.Next() {
+			var action Action
+			if err := json.Unmarshal(v, &action); err != nil {
+				return fmt.Errorf("offline: failed to unmarshal queued action %x: %w", k, err)
+			}
+			actions = append(actions, action)
+			keys = append(keys, append([]byte(nil), k...))
+		}
+
+		for _, k := range keys {
+			if err := b.Delete(k); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return actions, nil
+}
+
+// Len reports the number of actions currently queued.
+func (q *Queue) Len() int {
+	var n int
+	q.db.View(func(tx *bolt.Tx) error {
+		n = tx.Bucket(actionsBucket).Stats().KeyN
+		return nil
+	})
+	return n
+}
+
+// sequenceKey encodes a bbolt auto-increment sequence as a fixed-width,
+// lexicographically-ordered key, so a bucket Cursor walks it in
+// enqueue order.
+func sequenceKey(seq uint64) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, seq)
+	return key
+}
@@ -0,0 +1,82 @@
+package offline
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func openTestQueue(t *testing.T) *Queue {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "offline.db")
+	q, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	t.Cleanup(func() { q.Close() })
+	return q
+}
+
+func TestQueueEnqueueDequeueOrder(t *testing.T) {
+	q := openTestQueue(t)
+
+	for _, key := range []string{"a.example.com", "b.example.com", "c.example.com"} {
+		if err := q.Enqueue(Action{Kind: "event", Key: key, Payload: []byte(key)}); err != nil {
+			t.Fatalf("Enqueue(%s) failed: %v", key, err)
+		}
+	}
+
+	if got := q.Len(); got != 3 {
+		t.Fatalf("Expected Len 3, got %d", got)
+	}
+
+	actions, err := q.Dequeue(2)
+	if err != nil {
+		t.Fatalf("Dequeue failed: %v", err)
+	}
+	if len(actions) != 2 {
+		t.Fatalf("Expected 2 actions, got %d", len(actions))
+	}
+	if actions[0].Key != "a.example.com" || actions[1].Key != "b.example.com" {
+		t.Errorf("Expected FIFO order, got %q then %q", actions[0].Key, actions[1].Key)
+	}
+
+	if got := q.Len(); got != 1 {
+		t.Errorf("Expected Len 1 after dequeuing 2, got %d", got)
+	}
+}
+
+func TestQueueDequeueMoreThanAvailable(t *testing.T) {
+	q := openTestQueue(t)
+
+	if err := q.Enqueue(Action{Kind: "detection", Key: "example.com"}); err != nil {
+		t.Fatalf("Enqueue failed: %v", err)
+	}
+
+	actions, err := q.Dequeue(10)
+	if err != nil {
+		t.Fatalf("Dequeue failed: %v", err)
+	}
+	if len(actions) != 1 {
+		t.Fatalf("Expected 1 action, got %d", len(actions))
+	}
+	if q.Len() != 0 {
+		t.Errorf("Expected empty queue, got Len %d", q.Len())
+	}
+}
+
+func TestOpenCorruptFileReturnsErrCorruptQueue(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "corrupt.db")
+	if err := os.WriteFile(path, []byte("not a bbolt file"), 0600); err != nil {
+		t.Fatalf("failed to write corrupt fixture: %v", err)
+	}
+
+	_, err := Open(path)
+	if err == nil {
+		t.Fatal("Expected Open to fail on a corrupt file")
+	}
+	if !errors.Is(err, ErrCorruptQueue) {
+		t.Errorf("Expected err to wrap ErrCorruptQueue, got %v", err)
+	}
+}